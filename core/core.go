@@ -1,57 +1,138 @@
 package core
 
-import "github.com/artarts36/dbml-go/token"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artarts36/dbml-go/token"
+)
 
 // DBML structure.
 type DBML struct {
-	Project     Project
-	Tables      []Table
-	Enums       []Enum
-	Refs        []Ref
-	TableGroups []TableGroup
+	Project       Project
+	Tables        []Table
+	TablePartials []TablePartial
+	Enums         []Enum
+	Refs          []Ref
+	TableGroups   []TableGroup
+	Notes         []StickyNote
+
+	// Trivia contains file header comments in Leading and comments at the end of file in Inner.
+	Trivia Trivia
 }
 
 // Project ...
 type Project struct {
 	Name         string
-	Note         string
 	DatabaseType string
+	Note         string
+	// NoteRaw is note as written in DBML, with quotes and escape sequences.
+	NoteRaw string
+	// Settings contains values of unknown settings by lowercased name, e.g. version: '1.0'.
+	Settings map[string]string
+	// SettingElements are declarations of Settings in order of declaration.
+	SettingElements []SettingElement
+	// DatabaseTypeElement and NoteElement are positions of database_type and note in body.
+	DatabaseTypeElement Element
+	NoteElement         Element
+	Span                Span
+	Trivia              Trivia
 }
 
 // Column ...
 type Column struct {
-	Name     string
+	Name string
+	// Type is column type in canonical form, e.g. decimal(10,2) or int[].
 	Type     string
+	DataType ColumnType
 	Settings ColumnSetting
+	Span     Span
+	Trivia   Trivia
+}
+
+// ColumnType is structured column type: [schema.]name(arg1, arg2)[].
+type ColumnType struct {
+	// Schema is empty when type name is not schema qualified.
+	Schema string
+	Name   string
+	// Args of type as they written in DBML, string arguments keep their quotes and escapes.
+	Args []string
+	// ArrayDimensions is count of [] after type.
+	ArrayDimensions int
+	// Raw is type as written in DBML, e.g. decimal(10, 2) or "timestamp with time zone".
+	Raw string
 }
 
 // ColumnSetting ...
 type ColumnSetting struct {
 	Note      string
+	NoteRaw   string
 	PK        bool
 	Unique    bool
 	Default   ColumnDefault
-	Null      bool
+	Null      Nullability
 	Increment bool
+	Checks    []Check
 	Ref       struct {
-		Type RelationshipType
-		To   string
+		Type     RelationshipType
+		To       RelationshipEndpoint
+		OnDelete ReferentialAction
+		OnUpdate ReferentialAction
 	}
 }
 
+// Nullability of column.
+type Nullability int
+
+const (
+	// NullabilityUnspecified neither null nor not null is specified.
+	NullabilityUnspecified Nullability = iota
+	// NullabilityNullable column is marked as null.
+	NullabilityNullable
+	// NullabilityNotNull column is marked as not null.
+	NullabilityNotNull
+)
+
+// Check is check constraint: `expression` with optional name.
+type Check struct {
+	Name       string
+	Expression string
+	Span       Span
+	Trivia     Trivia
+}
+
 // Index ...
 type Index struct {
-	Fields   []string
+	Fields   []IndexField
 	Settings IndexSetting
+	Span     Span
+	Trivia   Trivia
+}
+
+// IndexFieldType ...
+type IndexFieldType int
+
+const (
+	// IndexFieldTypeColumn field is column name.
+	IndexFieldTypeColumn IndexFieldType = iota
+	// IndexFieldTypeExpression field is `expression`.
+	IndexFieldTypeExpression
+)
+
+// IndexField is column name or expression of index.
+type IndexField struct {
+	Value string
+	Type  IndexFieldType
 }
 
 // IndexSetting ...
 type IndexSetting struct {
-	Type   string
-	Name   string
-	Unique bool
-	PK     bool
-	Note   string
+	Type    string
+	Name    string
+	Unique  bool
+	PK      bool
+	Note    string
+	NoteRaw string
 }
 
 // RelationshipType ...
@@ -66,46 +147,140 @@ const (
 	OneToMany
 	// ManyToOne n - 1.
 	ManyToOne
+	// ManyToMany n - n.
+	ManyToMany
 )
 
 // Relationship ...
 type Relationship struct {
-	From string
-	To   string
-	Type RelationshipType
+	From     RelationshipEndpoint
+	To       RelationshipEndpoint
+	Type     RelationshipType
+	OnDelete ReferentialAction
+	OnUpdate ReferentialAction
+	Span     Span
+	Trivia   Trivia
+}
+
+// RelationshipEndpoint is one side of relationship: table and ordered list of its columns.
+type RelationshipEndpoint struct {
+	// Schema is empty when table name is not schema qualified.
+	Schema  string
+	Table   string
+	Columns []string
+}
+
+// String returns endpoint as it written in DBML: [schema.]table.column or [schema.]table.(column1, column2).
+func (e RelationshipEndpoint) String() string {
+	table := QualifiedName(e.Schema, e.Table)
+	if len(e.Columns) == 1 {
+		return fmt.Sprintf("%s.%s", table, e.Columns[0])
+	}
+	return fmt.Sprintf("%s.(%s)", table, strings.Join(e.Columns, ", "))
+}
+
+// ReferentialAction describes behaviour of relationship on delete or update of referenced row.
+type ReferentialAction int
+
+const (
+	// ReferentialActionNone action is not specified.
+	ReferentialActionNone ReferentialAction = iota
+	// ReferentialActionCascade cascade.
+	ReferentialActionCascade
+	// ReferentialActionRestrict restrict.
+	ReferentialActionRestrict
+	// ReferentialActionSetNull set null.
+	ReferentialActionSetNull
+	// ReferentialActionSetDefault set default.
+	ReferentialActionSetDefault
+	// ReferentialActionNoAction no action.
+	ReferentialActionNoAction
+)
+
+// String returns action as it written in DBML.
+func (a ReferentialAction) String() string {
+	switch a {
+	case ReferentialActionCascade:
+		return "cascade"
+	case ReferentialActionRestrict:
+		return "restrict"
+	case ReferentialActionSetNull:
+		return "set null"
+	case ReferentialActionSetDefault:
+		return "set default"
+	case ReferentialActionNoAction:
+		return "no action"
+	default:
+		return ""
+	}
 }
 
 // RelationshipMap ...
 var RelationshipMap = map[token.Token]RelationshipType{
-	token.GTR: ManyToOne,
-	token.LSS: OneToMany,
-	token.SUB: OneToOne,
+	token.GTR:    ManyToOne,
+	token.LSS:    OneToMany,
+	token.SUB:    OneToOne,
+	token.LSSGTR: ManyToMany,
 }
 
 // Ref ...
 type Ref struct {
-	// TODO:
-	//		- handle Ref
 	Name          string // optional
 	Relationships []Relationship
+	Span          Span
+	Trivia        Trivia
 }
 
 // Enum ...
 type Enum struct {
+	// Schema is empty when enum name is not schema qualified.
+	Schema string
 	Name   string
 	Values []EnumValue
+	Span   Span
+	Trivia Trivia
 }
 
 // EnumValue ...
 type EnumValue struct {
-	Name string
-	Note string
+	Name    string
+	Note    string
+	NoteRaw string
+	Span    Span
+	Trivia  Trivia
+}
+
+// StickyNote is standalone note: Note name { '...' }.
+type StickyNote struct {
+	Name    string
+	Content string
+	// ContentRaw is content as written in DBML, with quotes and escape sequences.
+	ContentRaw string
+	// ContentElement is position of content in body.
+	ContentElement Element
+	Span           Span
+	Trivia         Trivia
 }
 
 // TableGroup ...
 type TableGroup struct {
-	// TODO:
-	// --  handle for table group
-	Name    string
-	Members []string
+	Name  string
+	Color string
+	// Note is set with note setting or with Note: in group body.
+	Note    string
+	NoteRaw string
+	Members []TableGroupMember
+	// NoteElement is position of Note: in body.
+	NoteElement Element
+	Span        Span
+	Trivia      Trivia
+}
+
+// TableGroupMember is table of group.
+type TableGroupMember struct {
+	// Schema is empty when table name is not schema qualified.
+	Schema string
+	Name   string
+	Span   Span
+	Trivia Trivia
 }
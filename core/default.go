@@ -8,10 +8,14 @@ const (
 	ColumnDefaultTypeString
 	ColumnDefaultTypeExpression
 	ColumnDefaultTypeBoolean
+	ColumnDefaultTypeNull
 )
 
 type ColumnDefault struct {
 	Raw   string
 	Value interface{}
 	Type  ColumnDefaultType
+	// Source is default as written in DBML, e.g. 'it\'s' with quotes and escape sequences,
+	// while Raw is string content or literal.
+	Source string
 }
@@ -0,0 +1,85 @@
+package core
+
+import "strings"
+
+// DefaultSchema is schema of tables and enums declared without schema.
+const DefaultSchema = "public"
+
+// Lookup finds tables and enums of DBML by name.
+//
+// Names are compared case-insensitively, tables and enums without schema belong to DefaultSchema.
+// The first definition wins when name is duplicated.
+type Lookup struct {
+	tables  map[string]*Table
+	aliases map[string]*Table
+	enums   map[string]*Enum
+}
+
+// NewLookup returns lookup of tables and enums of dbml, found definitions point to dbml.
+func NewLookup(dbml *DBML) *Lookup {
+	l := &Lookup{
+		tables:  make(map[string]*Table, len(dbml.Tables)),
+		aliases: map[string]*Table{},
+		enums:   make(map[string]*Enum, len(dbml.Enums)),
+	}
+
+	for i := range dbml.Tables {
+		table := &dbml.Tables[i]
+		if _, ok := l.tables[LookupKey(table.Schema, table.Name)]; !ok {
+			l.tables[LookupKey(table.Schema, table.Name)] = table
+		}
+		if _, ok := l.aliases[strings.ToLower(table.As)]; table.As != "" && !ok {
+			l.aliases[strings.ToLower(table.As)] = table
+		}
+	}
+	for i := range dbml.Enums {
+		enum := &dbml.Enums[i]
+		if _, ok := l.enums[LookupKey(enum.Schema, enum.Name)]; !ok {
+			l.enums[LookupKey(enum.Schema, enum.Name)] = enum
+		}
+	}
+
+	return l
+}
+
+// Table finds table by name or by alias when schema is empty.
+func (l *Lookup) Table(schema, name string) *Table {
+	if table, ok := l.tables[LookupKey(schema, name)]; ok {
+		return table
+	}
+	if schema == "" {
+		return l.aliases[strings.ToLower(name)]
+	}
+	return nil
+}
+
+// Enum finds enum by name.
+func (l *Lookup) Enum(schema, name string) *Enum {
+	return l.enums[LookupKey(schema, name)]
+}
+
+// Column finds column of table by name case-insensitively.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// LookupKey returns key of table or enum name: lowercased name qualified with schema, DefaultSchema when schema is empty.
+func LookupKey(schema, name string) string {
+	if schema == "" {
+		schema = DefaultSchema
+	}
+	return strings.ToLower(schema + "." + name)
+}
+
+// QualifiedName returns name with schema prefix when schema is specified.
+func QualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveTablePartials returns copy of DBML where columns, indexes, note and settings
+// of injected partials are merged into tables.
+//
+// Table's own definitions take precedence over partials, among partials the last injected wins.
+// Injected columns are placed at position of injection, indexes and checks of partials precede table's own ones.
+func (d *DBML) ResolveTablePartials() (*DBML, error) {
+	partials := make(map[string]*TablePartial, len(d.TablePartials))
+	for i := range d.TablePartials {
+		partials[strings.ToLower(d.TablePartials[i].Name)] = &d.TablePartials[i]
+	}
+
+	resolved := *d
+	resolved.Tables = make([]Table, 0, len(d.Tables))
+	for _, table := range d.Tables {
+		if len(table.Partials) == 0 {
+			resolved.Tables = append(resolved.Tables, table)
+			continue
+		}
+
+		injected := make([]*TablePartial, 0, len(table.Partials))
+		for _, injection := range table.Partials {
+			partial, ok := partials[strings.ToLower(injection.Name)]
+			if !ok {
+				return nil, fmt.Errorf("table %s: table partial %s not found", table.Name, injection.Name)
+			}
+			injected = append(injected, partial)
+		}
+
+		resolved.Tables = append(resolved.Tables, resolveTable(table, injected))
+	}
+
+	return &resolved, nil
+}
+
+func resolveTable(table Table, injected []*TablePartial) Table {
+	type candidate struct {
+		column Column
+		// precedence of definition: table's own columns have the highest one
+		precedence int
+	}
+
+	ownPrecedence := len(injected)
+	candidates := make([]candidate, 0, len(table.Columns))
+	injectedIndexes := []Index{}
+	injectedChecks := []Check{}
+	settings := TableSettings{}
+	note, noteRaw := "", ""
+
+	injection := 0
+	for position := 0; position <= len(table.Columns); position++ {
+		for ; injection < len(table.Partials) && table.Partials[injection].Position == position; injection++ {
+			partial := injected[injection]
+			for _, column := range partial.Columns {
+				candidates = append(candidates, candidate{column: column, precedence: injection})
+			}
+			injectedIndexes = append(injectedIndexes, partial.Indexes...)
+			injectedChecks = append(injectedChecks, partial.Checks...)
+			settings = mergeTableSettings(settings, partial.Settings)
+			if partial.Note != "" {
+				note, noteRaw = partial.Note, partial.NoteRaw
+			}
+		}
+		if position < len(table.Columns) {
+			candidates = append(candidates, candidate{column: table.Columns[position], precedence: ownPrecedence})
+		}
+	}
+
+	winners := map[string]int{}
+	for _, c := range candidates {
+		name := strings.ToLower(c.column.Name)
+		if c.precedence >= winners[name] {
+			winners[name] = c.precedence
+		}
+	}
+
+	columns := make([]Column, 0, len(winners))
+	for _, c := range candidates {
+		name := strings.ToLower(c.column.Name)
+		if precedence, ok := winners[name]; ok && precedence == c.precedence {
+			columns = append(columns, c.column)
+			delete(winners, name)
+		}
+	}
+
+	table.Columns = columns
+	table.Indexes = append(injectedIndexes, table.Indexes...)
+	table.Checks = append(injectedChecks, table.Checks...)
+	table.Settings = mergeTableSettings(settings, table.Settings)
+	if table.Note == "" {
+		table.Note, table.NoteRaw = note, noteRaw
+	}
+	table.Partials = nil
+
+	return table
+}
+
+// mergeTableSettings returns settings where values of override replace values of base.
+func mergeTableSettings(base, override TableSettings) TableSettings {
+	if override.HeaderColor != "" {
+		base.HeaderColor = override.HeaderColor
+	}
+	if override.Note != "" {
+		base.Note, base.NoteRaw = override.Note, override.NoteRaw
+	}
+	if len(override.Extra) > 0 {
+		extra := make(map[string]string, len(base.Extra)+len(override.Extra))
+		for name, value := range base.Extra {
+			extra[name] = value
+		}
+		for name, value := range override.Extra {
+			extra[name] = value
+		}
+		base.Extra = extra
+	}
+	if len(override.ExtraElements) > 0 {
+		elements := make([]SettingElement, 0, len(base.ExtraElements)+len(override.ExtraElements))
+		elements = append(elements, base.ExtraElements...)
+		positions := make(map[string]int, cap(elements))
+		for i, element := range elements {
+			positions[strings.ToLower(element.Name)] = i
+		}
+		for _, element := range override.ExtraElements {
+			name := strings.ToLower(element.Name)
+			if i, ok := positions[name]; ok {
+				elements[i] = element
+				continue
+			}
+			positions[name] = len(elements)
+			elements = append(elements, element)
+		}
+		base.ExtraElements = elements
+	}
+	return base
+}
@@ -0,0 +1,9 @@
+package core
+
+import "github.com/artarts36/dbml-go/token"
+
+// Span is location of definition in source, End points right after the last token of definition.
+type Span struct {
+	Start token.Position
+	End   token.Position
+}
@@ -2,15 +2,74 @@ package core
 
 // Table ...
 type Table struct {
+	// Schema is empty when table name is not schema qualified.
+	Schema  string
 	Name    string
 	As      string
 	Note    string
+	NoteRaw string
 	Columns []Column
 	Indexes []Index
+	Checks  []Check
+
+	// Partials injected to table with ~partial_name.
+	Partials []TablePartialInjection
 
 	Settings TableSettings
+	// NoteElement, IndexesElement and ChecksElement are positions of Note:, indexes and checks blocks in body.
+	NoteElement    Element
+	IndexesElement Element
+	ChecksElement  Element
+	Span           Span
+	Trivia         Trivia
 }
 
+// TablePartial is reusable set of columns, indexes and settings: TablePartial name { ... }.
+type TablePartial struct {
+	Name     string
+	Note     string
+	NoteRaw  string
+	Columns  []Column
+	Indexes  []Index
+	Checks   []Check
+	Settings TableSettings
+	// NoteElement, IndexesElement and ChecksElement are positions of Note:, indexes and checks blocks in body.
+	NoteElement    Element
+	IndexesElement Element
+	ChecksElement  Element
+	Span           Span
+	Trivia         Trivia
+}
+
+// TablePartialInjection is ~partial_name inside table.
+type TablePartialInjection struct {
+	Name string
+	// Position is count of table's own columns defined before injection.
+	Position int
+	Span     Span
+	Trivia   Trivia
+}
+
+// TableSettings is settings of table: [headercolor: #3498DB, note: '...'].
 type TableSettings struct {
 	HeaderColor string
+	// Note is set with note setting, note in table body is Table.Note.
+	Note    string
+	NoteRaw string
+	// Extra contains values of unknown settings by lowercased name, value of flag is empty.
+	Extra map[string]string
+	// ExtraElements are declarations of Extra in order of declaration.
+	ExtraElements []SettingElement
+}
+
+// SettingElement is declaration of unknown setting: name or name: value.
+// Value of setting is stored in settings map by lowercased name.
+type SettingElement struct {
+	// Name is name as written in DBML.
+	Name string
+	// Raw is value as written in DBML, e.g. 16, 'x' or #fff, it is empty for flag.
+	Raw  string
+	Span Span
+	// Trivia is filled only for settings in definition body, e.g. in project.
+	Trivia Trivia
 }
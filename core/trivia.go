@@ -0,0 +1,30 @@
+package core
+
+// Comment is comment with delimiters, e.g. "// text".
+type Comment struct {
+	Text string
+	// EmptyLineBefore is true when line before comment is empty.
+	EmptyLineBefore bool
+	Span            Span
+}
+
+// Trivia is comments and empty lines around definition, it is filled only in parser.ParseComments mode.
+type Trivia struct {
+	// Leading comments placed before definition.
+	Leading []Comment
+	// Header comments placed after opening brace of definition on the same line.
+	Header []Comment
+	// Trailing comments placed after definition on the same line.
+	Trailing []Comment
+	// Inner comments placed inside definition and not attached to nested definitions,
+	// e.g. comments before closing brace of table.
+	Inner []Comment
+	// EmptyLineBefore is true when line before definition is empty.
+	EmptyLineBefore bool
+}
+
+// Element is position and comments of body element stored in plain field, e.g. Note: in table body.
+type Element struct {
+	Span   Span
+	Trivia Trivia
+}
@@ -0,0 +1,49 @@
+// Package errlist implements list of errors reported at once, shared by parser and validate.
+package errlist
+
+import (
+	"errors"
+	"fmt"
+)
+
+// List of errors, it is reported as its first error with count of the rest.
+type List[E error] []E
+
+func (l List[E]) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Is reports whether any error in the list matches target, used by errors.Is.
+func (l List[E]) Is(target error) bool {
+	for _, err := range l {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in the list that matches target, used by errors.As.
+func (l List[E]) As(target any) bool {
+	for _, err := range l {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns errors of list for errors package of Go 1.20 and later.
+func (l List[E]) Unwrap() []error {
+	errs := make([]error, 0, len(l))
+	for _, err := range l {
+		errs = append(errs, err)
+	}
+	return errs
+}
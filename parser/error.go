@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artarts36/dbml-go/internal/errlist"
+	"github.com/artarts36/dbml-go/token"
+)
+
+// ParseError describes invalid token found while parsing.
+type ParseError struct {
+	// Position is start of found token.
+	token.Position
+
+	// Token and Literal found at Position.
+	Token   token.Token
+	Literal string
+
+	// Expected alternatives, empty when Message is set.
+	Expected []string
+	// Message describes error when it is not about expected tokens.
+	Message string
+
+	// Context is path to definition being parsed, e.g. "table users > column id".
+	Context string
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = fmt.Sprintf(
+			"invalid token '%s' determined as %s, expected: '%s'",
+			e.Literal,
+			e.Token,
+			strings.Join(e.Expected, " | "),
+		)
+	}
+	if e.Context != "" {
+		msg = fmt.Sprintf("%s: %s", e.Context, msg)
+	}
+	return fmt.Sprintf("[%d:%d] %s", e.Line, e.Column, msg)
+}
+
+// ErrorList is list of errors collected in AllErrors mode.
+type ErrorList = errlist.List[*ParseError]
+
+// expect returns error about current token, which is not one of expected.
+func (p *Parser) expect(expected ...string) error {
+	return &ParseError{
+		Position: p.pos,
+		Token:    p.token,
+		Literal:  p.lit,
+		Expected: expected,
+		Context:  strings.Join(p.scope, " > "),
+	}
+}
+
+// errorf returns error with message at current token.
+func (p *Parser) errorf(format string, args ...any) error {
+	return &ParseError{
+		Position: p.pos,
+		Token:    p.token,
+		Literal:  p.lit,
+		Message:  fmt.Sprintf(format, args...),
+		Context:  strings.Join(p.scope, " > "),
+	}
+}
+
+// enter pushes definition to error context, returned function pops it.
+func (p *Parser) enter(definition string) func() {
+	p.scope = append(p.scope, definition)
+	return func() {
+		p.scope = p.scope[:len(p.scope)-1]
+	}
+}
+
+// rename replaces definition entered last, e.g. when its name is parsed after entering.
+func (p *Parser) rename(definition string) {
+	p.scope[len(p.scope)-1] = definition
+}
@@ -12,6 +12,10 @@ func Parse(ctx context.Context, spec io.Reader) (*core.DBML, error) {
 	return NewParser(scanner.NewScanner(spec)).Parse(ctx)
 }
 
+func ParseWithMode(ctx context.Context, spec io.Reader, mode Mode) (*core.DBML, error) {
+	return NewParserWithMode(scanner.NewScanner(spec), mode, NoopLogger).Parse(ctx)
+}
+
 func ParseWithDebug(ctx context.Context, spec io.Reader, logger Logger) (*core.DBML, error) {
 	return NewParserWithLogger(scanner.NewScanner(spec), logger).Parse(ctx)
 }
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -12,27 +13,71 @@ import (
 	"github.com/artarts36/dbml-go/token"
 )
 
+// columnSettingNames are settings expected in column settings.
+var columnSettingNames = []string{
+	"pk", "primary key", "null", "not null", "unique", "increment", "default", "note", "ref", "check",
+}
+
 // Parser declaration.
 type Parser struct {
 	s *scanner.Scanner
 
-	// current token & literal
+	// current token, literal & position
 	token token.Token
 	lit   string
+	pos   token.Position
+	end   token.Position
+
+	// end of previous token
+	prevEnd token.Position
+
+	// lookahead token, literal & position, filled by peek
+	peeked    bool
+	peekToken token.Token
+	peekLit   string
+	peekPos   token.Position
+	peekEnd   token.Position
+
+	// definitions being parsed, used as error context
+	scope []string
+	// depth of braces at current token
+	depth int
+
+	// comments and lines with tokens, collected in ParseComments mode
+	comments []core.Comment
+	lines    map[int]bool
 
+	mode   Mode
 	logger Logger
 }
 
+// Mode controls parser behaviour.
+type Mode uint
+
+const (
+	// AllErrors mode resynchronises parser at the next top level definition after error,
+	// Parse returns partial DBML with ErrorList.
+	AllErrors Mode = 1 << iota
+	// ParseComments mode attaches comments and empty lines to definitions as core.Trivia.
+	ParseComments
+)
+
 // NewParser ...
 func NewParser(s *scanner.Scanner) *Parser {
 	return NewParserWithLogger(s, NoopLogger)
 }
 
 func NewParserWithLogger(s *scanner.Scanner, logger Logger) *Parser {
+	return NewParserWithMode(s, 0, logger)
+}
+
+func NewParserWithMode(s *scanner.Scanner, mode Mode, logger Logger) *Parser {
 	return &Parser{
 		s:      s,
 		token:  token.ILLEGAL,
 		lit:    "",
+		lines:  map[int]bool{},
+		mode:   mode,
 		logger: logger,
 	}
 }
@@ -40,96 +85,281 @@ func NewParserWithLogger(s *scanner.Scanner, logger Logger) *Parser {
 // Parse ...
 func (p *Parser) Parse(ctx context.Context) (*core.DBML, error) {
 	dbml := &core.DBML{}
+	errs := ErrorList{}
+	advance := true
 	for {
-		p.next()
-		switch p.token {
-		case token.PROJECT:
-			project, err := p.parseProject()
-			if err != nil {
-				return nil, err
+		if advance {
+			p.next()
+		}
+		advance = true
+
+		if p.token == token.EOF {
+			if p.mode&ParseComments != 0 {
+				attachComments(dbml, p.comments, p.lines)
 			}
-			p.debug(ctx, "found project", map[string]any{"project": project})
-			dbml.Project = *project
-		case token.TABLE:
-			table, err := p.parseTable(ctx)
-			if err != nil {
-				return nil, err
+			if len(errs) > 0 {
+				return dbml, errs
 			}
-			p.debug(ctx, "found table", map[string]any{"table": table})
+			return dbml, nil
+		}
 
-			// TODO:
-			// * register table to tables map, for check ref
-			dbml.Tables = append(dbml.Tables, *table)
+		err := p.parseDefinition(ctx, dbml)
+		if err == nil {
+			continue
+		}
 
-		case token.REF:
-			ref, err := p.parseRefs()
+		var parseErr *ParseError
+		if p.mode&AllErrors == 0 || !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		errs = append(errs, parseErr)
+		advance = p.sync()
+		p.debug(ctx, "recovered after error", map[string]any{
+			"error": err.Error(),
+			"token": p.token.String(),
+			"lit":   p.lit,
+		})
+	}
+}
+
+// parseDefinition parses top level definition and adds it to dbml.
+func (p *Parser) parseDefinition(ctx context.Context, dbml *core.DBML) error {
+	switch p.token {
+	case token.PROJECT:
+		project, err := p.parseProject()
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found project", map[string]any{"project": project})
+		dbml.Project = *project
+	case token.TABLE:
+		table, err := p.parseTable(ctx)
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found table", map[string]any{"table": table})
+		dbml.Tables = append(dbml.Tables, *table)
+
+	case token.TABLEPARTIAL:
+		partial, err := p.parseTablePartial(ctx)
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found table partial", map[string]any{"table_partial": partial})
+		dbml.TablePartials = append(dbml.TablePartials, *partial)
+
+	case token.REF:
+		ref, err := p.parseRefs()
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found refs", map[string]any{
+			"ref": ref,
+		})
+		dbml.Refs = append(dbml.Refs, *ref)
+
+	case token.ENUM:
+		enum, err := p.parseEnum()
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found enum", map[string]any{
+			"enum": enum,
+		})
+		dbml.Enums = append(dbml.Enums, *enum)
+
+	case token.TABLEGROUP:
+		tableGroup, err := p.parseTableGroup()
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found table group", map[string]any{
+			"table_group": tableGroup,
+		})
+		dbml.TableGroups = append(dbml.TableGroups, *tableGroup)
+
+	case token.NOTE:
+		note, err := p.parseStickyNote()
+		if err != nil {
+			return err
+		}
+		p.debug(ctx, "found sticky note", map[string]any{
+			"note": note,
+		})
+		dbml.Notes = append(dbml.Notes, *note)
+	default:
+		p.debug(ctx, "got unexpected token", map[string]any{
+			"token": p.token.String(),
+			"lit":   p.lit,
+		})
+		return p.expect("Project", "Ref", "Table", "TablePartial", "Enum", "TableGroup", "Note")
+	}
+
+	return nil
+}
+
+// sync skips tokens until the end of current top level definition or the start of the next one.
+// Definition keyword at the start of line is the start of the next definition at any depth,
+// so definition with unclosed brace does not hide following ones.
+// Returns false when current token is already the start of the next definition.
+func (p *Parser) sync() bool {
+	if p.token == token.EOF || p.resync(p.token, p.pos) {
+		return false
+	}
+	for {
+		if p.token == token.RBRACE && p.depth == 0 {
+			return true
+		}
+		next := p.peek()
+		if next == token.EOF || p.resync(next, p.peekPos) {
+			return true
+		}
+		p.next()
+	}
+}
+
+// resync reports whether token at pos starts the next top level definition, depth is reset when it does.
+func (p *Parser) resync(t token.Token, pos token.Position) bool {
+	if !isDefinition(t) || (p.depth > 0 && pos.Column != 1) {
+		return false
+	}
+	p.depth = 0
+	return true
+}
+
+func isDefinition(t token.Token) bool {
+	switch t {
+	case token.PROJECT, token.TABLE, token.TABLEPARTIAL, token.REF, token.ENUM, token.TABLEGROUP, token.NOTE:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Parser) parseTableGroup() (*core.TableGroup, error) {
+	tableGroup := &core.TableGroup{}
+	start := p.pos
+	p.next()
+	if p.token != token.IDENT && p.token != token.DSTRING {
+		return nil, p.errorf("TableGroup name is invalid: %s", p.lit)
+	}
+	tableGroup.Name = p.lit
+	defer p.enter("table group " + tableGroup.Name)()
+	p.next()
+	if p.token == token.LBRACK {
+		if err := p.parseTableGroupSettings(tableGroup); err != nil {
+			return nil, err
+		}
+		p.next() // remove ']'
+	}
+	if p.token != token.LBRACE {
+		return nil, p.expect("{")
+	}
+	p.next()
+
+	for {
+		switch {
+		case p.token == token.RBRACE:
+			tableGroup.Span = p.span(start)
+			return tableGroup, nil
+		case p.token == token.NOTE && p.peek() == token.COLON:
+			noteStart := p.pos
+			p.next()
+			note, err := p.parseString()
 			if err != nil {
 				return nil, err
 			}
-			p.debug(ctx, "found refs", map[string]any{
-				"ref": ref,
-			})
-
-			// TODO:
-			// * Check refs is valid or not (by tables map)
-			dbml.Refs = append(dbml.Refs, *ref)
-
-		case token.ENUM:
-			enum, err := p.parseEnum()
+			tableGroup.Note = note
+			tableGroup.NoteRaw = p.raw()
+			tableGroup.NoteElement.Span = p.span(noteStart)
+		case isName(p.token):
+			memberStart := p.pos
+			schema, name, err := p.parseQualifiedName()
 			if err != nil {
 				return nil, err
 			}
-			p.debug(ctx, "found enum", map[string]any{
-				"enum": enum,
+			tableGroup.Members = append(tableGroup.Members, core.TableGroupMember{
+				Schema: schema,
+				Name:   name,
+				Span:   p.span(memberStart),
 			})
-			dbml.Enums = append(dbml.Enums, *enum)
+		default:
+			return nil, p.expect("table_name", "note", "}")
+		}
+		p.next()
+	}
+}
+
+// parseTableGroupSettings parses [color: #3498DB, note: '...'], current token must be '['.
+func (p *Parser) parseTableGroupSettings(tableGroup *core.TableGroup) error {
+	commaAllowed := false
 
-		case token.TABLEGROUP:
-			tableGroup, err := p.parseTableGroup()
+	for {
+		p.next()
+		switch {
+		case p.token == token.IDENT && strings.ToLower(p.lit) == "color":
+			p.next()
+			if p.token != token.COLON {
+				return p.expect(":")
+			}
+			p.next()
+			if p.token != token.COLOR {
+				return p.expect("#color")
+			}
+			tableGroup.Color = p.lit
+		case p.token == token.NOTE:
+			note, err := p.parseDescription()
 			if err != nil {
-				return nil, err
+				return err
 			}
-			p.debug(ctx, "found table group", map[string]any{
-				"table_group": tableGroup,
-			})
-			dbml.TableGroups = append(dbml.TableGroups, *tableGroup)
-		case token.EOF:
-			return dbml, nil
+			tableGroup.Note = note
+			tableGroup.NoteRaw = p.raw()
+		case p.token == token.COMMA:
+			if !commaAllowed {
+				return p.expect("color", "note")
+			}
+		case p.token == token.RBRACK:
+			return nil
 		default:
-			p.debug(ctx, "got unexpected token", map[string]any{
-				"token": p.token.String(),
-				"lit":   p.lit,
-			})
-			return nil, p.expect("Project, Ref, Table, Enum, TableGroup")
+			return p.expect("color", "note")
 		}
+		commaAllowed = !commaAllowed
 	}
 }
 
-func (p *Parser) parseTableGroup() (*core.TableGroup, error) {
-	tableGroup := &core.TableGroup{}
+func (p *Parser) parseStickyNote() (*core.StickyNote, error) {
+	note := &core.StickyNote{}
+	start := p.pos
 	p.next()
-	if p.token != token.IDENT && p.token != token.DSTRING {
-		return nil, fmt.Errorf("TableGroup name is invalid: %s", p.lit)
+	if !isName(p.token) {
+		return nil, p.expect("note_name")
 	}
-	tableGroup.Name = p.lit
+	note.Name = p.lit
+	defer p.enter("note " + note.Name)()
 	p.next()
 	if p.token != token.LBRACE {
 		return nil, p.expect("{")
 	}
-	p.next()
 
-	for p.token == token.IDENT || p.token == token.DSTRING {
-		tableGroup.Members = append(tableGroup.Members, p.lit)
-		p.next()
+	content, err := p.parseString()
+	if err != nil {
+		return nil, err
 	}
+	note.Content = content
+	note.ContentRaw = p.raw()
+	note.ContentElement.Span = core.Span{Start: p.pos, End: p.end}
+
+	p.next()
 	if p.token != token.RBRACE {
 		return nil, p.expect("}")
 	}
-	return tableGroup, nil
+	note.Span = p.span(start)
+	return note, nil
 }
 
 func (p *Parser) parseRefs() (*core.Ref, error) {
 	ref := &core.Ref{}
+	start := p.pos
 	p.next()
 
 	// Handle for Ref <optional_name>...
@@ -137,6 +367,7 @@ func (p *Parser) parseRefs() (*core.Ref, error) {
 		ref.Name = p.lit
 		p.next()
 	}
+	defer p.enter(strings.TrimSpace("ref " + ref.Name))()
 
 	// Ref: from > to
 	if p.token == token.COLON {
@@ -146,6 +377,7 @@ func (p *Parser) parseRefs() (*core.Ref, error) {
 			return nil, err
 		}
 		ref.Relationships = append(ref.Relationships, *rel)
+		ref.Span = p.span(start)
 		return ref, nil
 	}
 
@@ -154,63 +386,88 @@ func (p *Parser) parseRefs() (*core.Ref, error) {
 
 		for {
 			if p.token == token.RBRACE {
+				ref.Span = p.span(start)
 				return ref, nil
-			} else if p.token == token.IDENT || p.token == token.DSTRING {
+			} else if isName(p.token) {
 				rel, err := p.parseRelationship()
 				if err != nil {
 					return nil, err
 				}
 				ref.Relationships = append(ref.Relationships, *rel)
 			} else {
-				return nil, p.expect("Ref: { from > to }")
+				return nil, p.expect("table.column_name", "}")
 			}
 			p.next()
 		}
 	}
 
-	return nil, p.expect("Ref: | Refs {}")
+	return nil, p.expect(":", "{")
 }
 
 func (p *Parser) parseRelationship() (*core.Relationship, error) {
 	rel := &core.Relationship{}
-	if p.token != token.IDENT && p.token != token.DSTRING {
+	start := p.pos
+	if !isName(p.token) {
 		return nil, p.expect("(rel from) table.column_name")
 	}
 
-	rel.From = p.lit
+	from, err := p.parseRelationshipEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	rel.From = *from
 
 	p.next()
 	if reltype, ok := core.RelationshipMap[p.token]; ok {
 		rel.Type = reltype
 	} else {
-		return nil, p.expect("> | < | -")
+		return nil, p.expect(">", "<", "-", "<>")
 	}
 
 	p.next()
-	if p.token != token.IDENT {
+	if !isName(p.token) {
 		return nil, p.expect("(rel to) table.column_name")
 	}
-	rel.To = p.lit
+	to, err := p.parseRelationshipEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	rel.To = *to
+
+	if p.peek() == token.LBRACK {
+		p.next()
+		if err := p.parseReferentialActions(&rel.OnDelete, &rel.OnUpdate); err != nil {
+			return nil, err
+		}
+	}
+	rel.Span = p.span(start)
 	return rel, nil
 }
 
 func (p *Parser) parseTable(ctx context.Context) (*core.Table, error) {
 	table := &core.Table{}
+	start := p.pos
+	defer p.enter("table")()
 	p.next()
 	switch p.token {
 	case token.IDENT, token.DSTRING:
 		// pass
 	default:
 		if m, _ := regexp.MatchString("^[a-zA-Z1-9]+$", p.lit); !m {
-			return nil, fmt.Errorf("table name is invalid: %s", p.lit)
+			return nil, p.errorf("table name is invalid: %s", p.lit)
 		}
 	}
-	table.Name = p.lit
+	schema, name, err := p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	table.Schema = schema
+	table.Name = name
+	p.rename("table " + core.QualifiedName(schema, name))
 
 	p.next()
 
-	switch p.token {
-	case token.AS:
+	if p.token == token.AS {
 		// handle as
 		p.next()
 		switch p.token {
@@ -220,55 +477,97 @@ func (p *Parser) parseTable(ctx context.Context) (*core.Table, error) {
 			return nil, p.expect("as NAME")
 		}
 		p.next()
-		fallthrough
-	case token.LBRACK:
-		// handle parseColumn
+	}
+
+	if p.token == token.LBRACK {
 		tableSetting, err := p.parseTableSettings()
 		if err != nil {
-			return nil, fmt.Errorf("parse table settings: %w", err)
+			return nil, err
 		}
 		p.next() // remove ']'
 		table.Settings = *tableSetting
-		fallthrough
-	case token.LBRACE:
-		p.next()
-		for {
-			switch p.token {
-			case token.INDEXES:
-				indexes, err := p.parseIndexes(ctx)
+	}
+
+	if p.token != token.LBRACE {
+		return nil, p.expect("{")
+	}
+
+	if err := p.parseTableBody(ctx, table, true); err != nil {
+		return nil, err
+	}
+	table.Span = p.span(start)
+	return table, nil
+}
+
+// parseTableBody parses columns, indexes and note of table, current token must be '{'.
+func (p *Parser) parseTableBody(ctx context.Context, table *core.Table, allowPartials bool) error {
+	p.next()
+	for {
+		switch p.token {
+		case token.INDEXES:
+			start := p.pos
+			indexes, err := p.parseIndexes(ctx)
+			if err != nil {
+				return err
+			}
+			table.Indexes = indexes
+			table.IndexesElement.Span = core.Span{Start: start, End: p.prevEnd}
+		case token.TILDE:
+			if !allowPartials {
+				return p.expect("column_name", "indexes", "note")
+			}
+			start := p.pos
+			p.next()
+			if !isName(p.token) {
+				return p.expect("partial_name")
+			}
+			table.Partials = append(table.Partials, core.TablePartialInjection{
+				Name:     p.lit,
+				Position: len(table.Columns),
+				Span:     p.span(start),
+			})
+			p.next()
+		case token.RBRACE:
+			return nil
+		default:
+			columnName := p.lit
+			currentToken := p.token
+			start := p.pos
+			p.next()
+			if currentToken == token.NOTE && p.token == token.COLON {
+				note, err := p.parseString()
 				if err != nil {
-					return nil, err
+					return err
 				}
-				table.Indexes = indexes
-			case token.RBRACE:
-				return table, nil
-			default:
-				columnName := p.lit
-				currentToken := p.token
+				table.Note = note
+				table.NoteRaw = p.raw()
+				table.NoteElement.Span = p.span(start)
 				p.next()
-				if currentToken == token.NOTE && p.token == token.COLON {
-					note, err := p.parseString()
-					if err != nil {
-						return nil, err
-					}
-					table.Note = note
-					p.next()
-				} else {
-					column, err := p.parseColumn(ctx, columnName)
-					if err != nil {
-						return nil, err
-					}
-					table.Columns = append(table.Columns, *column)
+			} else if currentToken == token.IDENT && strings.ToLower(columnName) == "checks" && p.token == token.LBRACE {
+				checks, err := p.parseChecks()
+				if err != nil {
+					return err
 				}
+				table.Checks = append(table.Checks, checks...)
+				// element of several checks blocks spans all of them
+				if table.ChecksElement.Span.Start.Line == 0 {
+					table.ChecksElement.Span.Start = start
+				}
+				table.ChecksElement.Span.End = p.prevEnd
+			} else {
+				column, err := p.parseColumn(ctx, columnName, start)
+				if err != nil {
+					return err
+				}
+				table.Columns = append(table.Columns, *column)
 			}
 		}
-	default:
-		return nil, p.expect("{")
 	}
 }
 
 func (p *Parser) parseIndexes(ctx context.Context) ([]core.Index, error) {
 	indexes := []core.Index{}
+	defer p.enter("indexes")()
 
 	p.next()
 	if p.token != token.LBRACE {
@@ -293,13 +592,55 @@ func (p *Parser) parseIndexes(ctx context.Context) ([]core.Index, error) {
 	}
 }
 
+func (p *Parser) parseChecks() ([]core.Check, error) {
+	checks := []core.Check{}
+	defer p.enter("checks")()
+
+	p.next()
+	for {
+		switch p.token {
+		case token.RBRACE:
+			p.next() // pop }
+			return checks, nil
+		case token.EXPR:
+			start := p.pos
+			check := core.Check{
+				Expression: p.lit,
+			}
+			p.next()
+			if p.token == token.LBRACK {
+				// handle [name: 'check_name']
+				p.next()
+				if p.token != token.IDENT || strings.ToLower(p.lit) != "name" {
+					return nil, p.expect("name")
+				}
+				name, err := p.parseDescription()
+				if err != nil {
+					return nil, p.expect("name: 'check_name'")
+				}
+				check.Name = name
+				p.next()
+				if p.token != token.RBRACK {
+					return nil, p.expect("]")
+				}
+				p.next()
+			}
+			check.Span = core.Span{Start: start, End: p.prevEnd}
+			checks = append(checks, check)
+		default:
+			return nil, p.expect("`check expression`")
+		}
+	}
+}
+
 func (p *Parser) parseIndex() (*core.Index, error) {
 	index := &core.Index{}
+	start := p.pos
 
 	if p.token == token.LPAREN {
 		p.next()
-		for token.IsIdent(p.token) {
-			index.Fields = append(index.Fields, p.lit)
+		for isIndexField(p.token) {
+			index.Fields = append(index.Fields, p.indexField())
 			p.next()
 			if p.token == token.COMMA {
 				p.next()
@@ -308,10 +649,10 @@ func (p *Parser) parseIndex() (*core.Index, error) {
 		if p.token != token.RPAREN {
 			return nil, p.expect(")")
 		}
-	} else if token.IsIdent(p.token) {
-		index.Fields = append(index.Fields, p.lit)
+	} else if isIndexField(p.token) {
+		index.Fields = append(index.Fields, p.indexField())
 	} else {
-		return nil, p.expect("field_name")
+		return nil, p.expect("field_name", "`expression`")
 	}
 
 	p.next()
@@ -335,6 +676,7 @@ func (p *Parser) parseIndex() (*core.Index, error) {
 					return nil, p.expect("note: 'index note'")
 				}
 				index.Settings.Note = note
+				index.Settings.NoteRaw = p.raw()
 			case p.token == token.PK:
 				index.Settings.PK = true
 			case p.token == token.UNIQUE:
@@ -346,7 +688,7 @@ func (p *Parser) parseIndex() (*core.Index, error) {
 				}
 				p.next()
 				if p.token != token.IDENT || (p.lit != "hash" && p.lit != "btree") {
-					return nil, p.expect("hash|btree")
+					return nil, p.expect("hash", "btree")
 				}
 				index.Settings.Type = p.lit
 			case p.token == token.COMMA:
@@ -355,53 +697,59 @@ func (p *Parser) parseIndex() (*core.Index, error) {
 				}
 			case p.token == token.RBRACK:
 				p.next()
+				index.Span = core.Span{Start: start, End: p.prevEnd}
 				return index, nil
 			default:
-				return nil, p.expect("note|name|type|pk|unique")
+				return nil, p.expect("note", "name", "type", "pk", "unique")
 			}
 			commaAllowed = !commaAllowed
 		}
 	}
 
+	index.Span = core.Span{Start: start, End: p.prevEnd}
 	return index, nil
 }
 
-func (p *Parser) parseColumn(ctx context.Context, name string) (*core.Column, error) {
+// indexField returns index field of current token.
+func (p *Parser) indexField() core.IndexField {
+	if p.token == token.EXPR {
+		return core.IndexField{
+			Value: p.lit,
+			Type:  core.IndexFieldTypeExpression,
+		}
+	}
+	return core.IndexField{
+		Value: p.lit,
+		Type:  core.IndexFieldTypeColumn,
+	}
+}
+
+func isIndexField(t token.Token) bool {
+	return isName(t) || t == token.EXPR
+}
+
+func (p *Parser) parseColumn(ctx context.Context, name string, start token.Position) (*core.Column, error) {
 	column := &core.Column{
 		Name: name,
 	}
-	if p.token != token.IDENT {
-		return nil, p.expect("int, varchar,...")
+	defer p.enter("column " + name)()
+	columnType, err := p.parseColumnType()
+	if err != nil {
+		return nil, err
 	}
-	column.Type = p.lit
-	p.next()
+	column.Type = canonicalColumnType(columnType)
+	column.DataType = *columnType
 
-	// parse for type
-	switch p.token {
-	case token.LPAREN:
-		p.next()
-		if p.token != token.INT {
-			return nil, p.expect("int")
-		}
-		column.Type = fmt.Sprintf("%s(%s)", column.Type, p.lit)
-		p.next()
-		if p.token != token.RPAREN {
-			return nil, p.expect(token.RPAREN.String())
-		}
-		p.next()
-		if p.token != token.LBRACK {
-			break
-		}
-		fallthrough
-	case token.LBRACK:
+	if p.token == token.LBRACK {
 		// handle parseColumn
 		columnSetting, err := p.parseColumnSettings()
 		if err != nil {
-			return nil, fmt.Errorf("parse column settings: %w", err)
+			return nil, err
 		}
 		p.next() // remove ']'
 		column.Settings = *columnSetting
 	}
+	column.Span = core.Span{Start: start, End: p.prevEnd}
 
 	p.debug(ctx, "found column", map[string]any{
 		"column": column,
@@ -409,28 +757,103 @@ func (p *Parser) parseColumn(ctx context.Context, name string) (*core.Column, er
 	return column, nil
 }
 
+// parseColumnType parses type of column and moves to the token after type.
+func (p *Parser) parseColumnType() (*core.ColumnType, error) {
+	if !isName(p.token) {
+		return nil, p.expect("column_type")
+	}
+	start := p.pos
+	schema, name, err := p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	columnType := &core.ColumnType{
+		Schema: schema,
+		Name:   name,
+	}
+	p.next()
+
+	if p.token == token.LPAREN {
+		for p.token != token.RPAREN {
+			p.next()
+			argStart := p.pos
+			switch {
+			case p.token == token.INT, p.token == token.FLOAT, token.IsIdent(p.token):
+			case p.token == token.STRING:
+				// quote inside string argument is escaped by doubling it, e.g. 'it''s'
+				for p.peek() == token.STRING && p.peekPos.Offset == p.end.Offset {
+					p.next()
+				}
+			default:
+				return nil, p.expect("type argument")
+			}
+			columnType.Args = append(columnType.Args, p.s.Source(argStart, p.end))
+			p.next()
+			if p.token != token.COMMA && p.token != token.RPAREN {
+				return nil, p.expect(",", ")")
+			}
+		}
+		p.next()
+	}
+
+	// [] is array dimension only when written without spaces, e.g. int[], while int [] is empty settings
+	for p.token == token.LBRACK && p.pos.Offset == p.prevEnd.Offset &&
+		p.peek() == token.RBRACK && p.peekPos.Offset == p.end.Offset {
+		p.next()
+		p.next()
+		columnType.ArrayDimensions++
+	}
+	columnType.Raw = p.s.Source(start, p.prevEnd)
+
+	return columnType, nil
+}
+
+// canonicalColumnType returns type with arguments separated by comma without spaces, e.g. decimal(10,2)[].
+func canonicalColumnType(columnType *core.ColumnType) string {
+	s := columnType.Name
+	if columnType.Schema != "" {
+		s = fmt.Sprintf("%s.%s", columnType.Schema, s)
+	}
+	if len(columnType.Args) > 0 {
+		s = fmt.Sprintf("%s(%s)", s, strings.Join(columnType.Args, ","))
+	}
+	return s + strings.Repeat("[]", columnType.ArrayDimensions)
+}
+
 func (p *Parser) parseColumnDefault() (*core.ColumnDefault, error) {
+	start := p.pos
+	lit := p.lit
+	if p.token == token.SUB || p.token == token.ADD {
+		// signed number
+		p.next()
+		if p.token != token.INT && p.token != token.FLOAT {
+			return nil, p.expect("number")
+		}
+		lit += p.lit
+	}
+
 	colDef := &core.ColumnDefault{
-		Raw:   p.lit,
-		Value: p.lit,
-		Type:  core.ColumnDefaultTypeUnknown,
+		Raw:    lit,
+		Value:  lit,
+		Type:   core.ColumnDefaultTypeUnknown,
+		Source: p.s.Source(start, p.end),
 	}
 
 	switch p.token {
 	case token.STRING, token.DSTRING:
 		colDef.Type = core.ColumnDefaultTypeString
 	case token.INT:
-		intVal, err := strconv.Atoi(p.lit)
+		intVal, err := strconv.Atoi(lit)
 		if err != nil {
-			return nil, p.expect(fmt.Sprintf("default int value: %s", err.Error()))
+			return nil, p.errorf("invalid default int value: %s", err)
 		}
 
 		colDef.Value = intVal
 		colDef.Type = core.ColumnDefaultTypeNumber
 	case token.FLOAT:
-		floatVal, err := strconv.ParseFloat(p.lit, 4)
+		floatVal, err := strconv.ParseFloat(lit, 64)
 		if err != nil {
-			return nil, p.expect(fmt.Sprintf("default float value: %s", err.Error()))
+			return nil, p.errorf("invalid default float value: %s", err)
 		}
 
 		colDef.Value = floatVal
@@ -448,7 +871,7 @@ func (p *Parser) parseColumnDefault() (*core.ColumnDefault, error) {
 			return nil, p.expect("default value")
 		}
 	case token.NULL:
-		colDef.Type = core.ColumnDefaultTypeBoolean
+		colDef.Type = core.ColumnDefaultTypeNull
 		colDef.Value = nil
 	default:
 		return nil, p.expect("default value")
@@ -478,23 +901,44 @@ func (p *Parser) parseColumnSettings() (*core.ColumnSetting, error) {
 				return nil, p.expect(":")
 			}
 			p.next()
-			if p.token != token.LSS && p.token != token.GTR && p.token != token.SUB {
-				return nil, p.expect("< | > | -")
+			reltype, ok := core.RelationshipMap[p.token]
+			if !ok {
+				return nil, p.expect("<", ">", "-", "<>")
 			}
-			columnSetting.Ref.Type = core.RelationshipMap[p.token]
+			columnSetting.Ref.Type = reltype
 			p.next()
-			if p.token != token.IDENT {
+			if !isName(p.token) {
 				return nil, p.expect("table.column_id")
 			}
-			columnSetting.Ref.To = p.lit
-		case token.NOT:
-			p.next()
-			if p.token != token.NULL {
-				return nil, p.expect("null")
+			to, err := p.parseRelationshipEndpoint()
+			if err != nil {
+				return nil, err
+			}
+			columnSetting.Ref.To = *to
+			if p.peek() == token.LBRACK {
+				p.next()
+				err := p.parseReferentialActions(&columnSetting.Ref.OnDelete, &columnSetting.Ref.OnUpdate)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case token.NOT, token.NULL:
+			null, err := p.parseNullability(columnSetting.Null)
+			if err != nil {
+				return nil, err
 			}
-			columnSetting.Null = false
+			columnSetting.Null = null
 		case token.UNIQUE:
 			columnSetting.Unique = true
+		case token.IDENT:
+			if strings.ToLower(p.lit) != "check" {
+				return nil, p.expect(columnSettingNames...)
+			}
+			check, err := p.parseColumnCheck()
+			if err != nil {
+				return nil, err
+			}
+			columnSetting.Checks = append(columnSetting.Checks, *check)
 		case token.INCREMENT:
 			columnSetting.Increment = true
 		case token.DEFAULT:
@@ -517,56 +961,110 @@ func (p *Parser) parseColumnSettings() (*core.ColumnSetting, error) {
 				return nil, err
 			}
 			columnSetting.Note = str
+			columnSetting.NoteRaw = p.raw()
 		case token.COMMA:
 			if !commaAllowed {
-				return nil, p.expect("pk | primary key | unique")
+				return nil, p.expect(columnSettingNames...)
 			}
 		case token.RBRACK:
 			return columnSetting, nil
 		default:
-			return nil, p.expect("pk, primary key, unique")
+			return nil, p.expect(columnSettingNames...)
 		}
 		commaAllowed = !commaAllowed
 	}
 }
 
-func (p *Parser) parseProject() (*core.Project, error) {
-	project := &core.Project{}
-	p.next()
-	if p.token != token.IDENT && p.token != token.DSTRING {
-		return nil, p.expect("project_name")
+// parseNullability parses null or not null, which must not conflict with previous nullability of column.
+func (p *Parser) parseNullability(previous core.Nullability) (core.Nullability, error) {
+	null := core.NullabilityNullable
+	if p.token == token.NOT {
+		p.next()
+		if p.token != token.NULL {
+			return null, p.expect("null")
+		}
+		null = core.NullabilityNotNull
+	}
+	if previous != core.NullabilityUnspecified && previous != null {
+		return null, p.errorf("null and not null settings conflict")
 	}
+	return null, nil
+}
 
-	project.Name = p.lit
+// parseColumnCheck parses check: `expression`, current token must be check.
+func (p *Parser) parseColumnCheck() (*core.Check, error) {
+	start := p.pos
+	p.next()
+	if p.token != token.COLON {
+		return nil, p.expect(":")
+	}
 	p.next()
+	if p.token != token.EXPR {
+		return nil, p.expect("`check expression`")
+	}
+	return &core.Check{
+		Expression: p.lit,
+		Span:       p.span(start),
+	}, nil
+}
 
-	if p.token != token.LBRACE {
-		return nil, p.expect("{")
+func (p *Parser) parseProject() (*core.Project, error) {
+	project := &core.Project{}
+	start := p.pos
+	p.next()
+	switch p.token {
+	case token.IDENT, token.DSTRING:
+		project.Name = p.lit
+		defer p.enter("project " + project.Name)()
+		p.next()
+		if p.token != token.LBRACE {
+			return nil, p.expect("{")
+		}
+	case token.LBRACE:
+		// project name is optional
+		defer p.enter("project")()
+	default:
+		return nil, p.expect("project_name", "{")
 	}
 	for {
 		p.next()
-		switch p.token {
-		case token.IDENT:
-			switch p.lit {
-			case "database_type":
-				str, err := p.parseDescription()
-				if err != nil {
-					return nil, err
-				}
-				project.DatabaseType = str
-			default:
-				return nil, p.expect("database_type")
+		switch {
+		case p.token == token.IDENT && strings.EqualFold(p.lit, "database_type"):
+			elementStart := p.pos
+			str, err := p.parseDescription()
+			if err != nil {
+				return nil, err
 			}
-		case token.NOTE:
+			project.DatabaseType = str
+			project.DatabaseTypeElement.Span = p.span(elementStart)
+		case p.token == token.NOTE:
+			elementStart := p.pos
 			note, err := p.parseDescription()
 			if err != nil {
 				return nil, err
 			}
 			project.Note = note
-		case token.RBRACE:
+			project.NoteRaw = p.raw()
+			project.NoteElement.Span = p.span(elementStart)
+		case p.token == token.RBRACE:
+			project.Span = p.span(start)
 			return project, nil
+		case isName(p.token):
+			if p.peek() != token.COLON {
+				p.next()
+				return nil, p.expect(":")
+			}
+			element, value, err := p.parseSetting()
+			if err != nil {
+				return nil, err
+			}
+			if project.Settings == nil {
+				project.Settings = map[string]string{}
+			}
+			project.Settings[strings.ToLower(element.Name)] = value
+			project.SettingElements = appendSetting(project.SettingElements, *element)
 		default:
-			return nil, fmt.Errorf("invalid token %s", p.lit)
+			return nil, p.expect("database_type", "note", "setting_name", "}")
 		}
 	}
 }
@@ -577,7 +1075,7 @@ func (p *Parser) parseString() (string, error) {
 	case token.STRING, token.DSTRING, token.TSTRING:
 		return p.lit, nil
 	default:
-		return "", p.expect("string, double quote string, triple string")
+		return "", p.expect("string", "double quote string", "triple string")
 	}
 }
 
@@ -590,18 +1088,52 @@ func (p *Parser) parseDescription() (string, error) {
 }
 
 func (p *Parser) next() {
+	p.prevEnd = p.end
+	if p.peeked {
+		p.token, p.lit, p.pos, p.end = p.peekToken, p.peekLit, p.peekPos, p.peekEnd
+		p.peeked = false
+	} else {
+		p.token, p.lit, p.pos, p.end = p.read()
+	}
+
+	switch {
+	case p.token == token.LBRACE:
+		p.depth++
+	case p.token == token.RBRACE && p.depth > 0:
+		p.depth--
+	}
+}
+
+// peek returns next token without consuming it.
+func (p *Parser) peek() token.Token {
+	if !p.peeked {
+		p.peekToken, p.peekLit, p.peekPos, p.peekEnd = p.read()
+		p.peeked = true
+	}
+	return p.peekToken
+}
+
+func (p *Parser) read() (token.Token, string, token.Position, token.Position) {
 	for {
-		p.token, p.lit = p.s.Read()
-		// p.debug("token:", p.token.String(), "lit:", p.lit)
-		if p.token != token.COMMENT {
-			break
+		tok, lit := p.s.Read()
+		// p.debug("token:", tok.String(), "lit:", lit)
+		if p.mode&ParseComments != 0 {
+			p.collectTrivia(tok, lit)
+		}
+		if tok != token.COMMENT {
+			return tok, lit, p.s.Pos(), p.s.End()
 		}
 	}
 }
 
-func (p *Parser) expect(expected string) error {
-	l, c := p.s.LineInfo()
-	return fmt.Errorf("[%d:%d] invalid token '%s' determined as %s, expected: '%s'", l, c, p.lit, p.token, expected)
+// raw returns source text of current token, e.g. string with quotes and escape sequences.
+func (p *Parser) raw() string {
+	return p.s.Source(p.pos, p.end)
+}
+
+// span returns span from start to the end of current token.
+func (p *Parser) span(start token.Position) core.Span {
+	return core.Span{Start: start, End: p.end}
 }
 
 func (p *Parser) debug(ctx context.Context, msg string, params map[string]any) {
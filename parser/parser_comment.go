@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/token"
+)
+
+// triviaNode is definition which can own comments.
+type triviaNode struct {
+	span   core.Span
+	trivia *core.Trivia
+	// block is true for definition with body in braces
+	block    bool
+	children []*triviaNode
+}
+
+// collectTrivia remembers comment and lines occupied by token.
+func (p *Parser) collectTrivia(tok token.Token, lit string) {
+	if tok == token.EOF {
+		return
+	}
+
+	pos, end := p.s.Pos(), p.s.End()
+	for line := pos.Line; line <= end.Line; line++ {
+		p.lines[line] = true
+	}
+
+	if tok == token.COMMENT {
+		p.comments = append(p.comments, core.Comment{
+			Text:            lit,
+			EmptyLineBefore: pos.Line > 1 && !p.lines[pos.Line-1],
+			Span:            core.Span{Start: pos, End: end},
+		})
+	}
+}
+
+// attachComments attaches comments to the innermost definitions containing them:
+//   - comment on the same line after definition is trailing comment of definition;
+//   - comment on the same line after opening brace is header comment of definition;
+//   - comment before definition is leading comment of the next definition;
+//   - other comments are inner comments of definition containing them.
+//
+// Comments before the first definition separated from it by empty line are file header,
+// they are kept in DBML.Trivia.Leading.
+func attachComments(dbml *core.DBML, comments []core.Comment, lines map[int]bool) {
+	root := triviaTree(dbml)
+
+	var walk func(node *triviaNode)
+	walk = func(node *triviaNode) {
+		if node.span.Start.Line > 1 {
+			node.trivia.EmptyLineBefore = !lines[node.span.Start.Line-1]
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	for _, child := range root.children {
+		walk(child)
+	}
+
+	for _, comment := range comments {
+		attachComment(root, comment)
+	}
+
+	if len(root.children) > 0 {
+		dbml.Trivia.Leading = fileHeader(root.children[0].trivia)
+	}
+}
+
+// fileHeader moves comments separated from definition by empty line out of its leading comments.
+func fileHeader(trivia *core.Trivia) []core.Comment {
+	leading := trivia.Leading
+	split := 0
+	for i := range leading {
+		if i+1 < len(leading) && leading[i+1].EmptyLineBefore {
+			split = i + 1
+		}
+	}
+	if len(leading) > 0 && trivia.EmptyLineBefore {
+		split = len(leading)
+	}
+	if split == 0 {
+		return nil
+	}
+
+	trivia.Leading = leading[split:]
+	if len(trivia.Leading) == 0 {
+		trivia.Leading = nil
+	}
+	return leading[:split]
+}
+
+func attachComment(container *triviaNode, comment core.Comment) {
+	for _, child := range container.children {
+		if contains(child.span, comment.Span) {
+			attachComment(child, comment)
+			return
+		}
+	}
+
+	var prev *triviaNode
+	for _, child := range container.children {
+		if child.span.End.Offset > comment.Span.Start.Offset {
+			break
+		}
+		prev = child
+	}
+	if prev != nil && prev.span.End.Line == comment.Span.Start.Line {
+		prev.trivia.Trailing = append(prev.trivia.Trailing, comment)
+		return
+	}
+	if prev == nil && container.block && container.span.Start.Line == comment.Span.Start.Line {
+		container.trivia.Header = append(container.trivia.Header, comment)
+		return
+	}
+
+	for _, child := range container.children {
+		if child.span.Start.Offset >= comment.Span.End.Offset {
+			child.trivia.Leading = append(child.trivia.Leading, comment)
+			return
+		}
+	}
+
+	container.trivia.Inner = append(container.trivia.Inner, comment)
+}
+
+func contains(outer, inner core.Span) bool {
+	return outer.Start.Offset <= inner.Start.Offset && inner.End.Offset <= outer.End.Offset
+}
+
+// triviaTree returns tree of definitions ordered by position.
+func triviaTree(dbml *core.DBML) *triviaNode {
+	root := &triviaNode{trivia: &dbml.Trivia}
+
+	if project := &dbml.Project; project.Span != (core.Span{}) {
+		node := root.addBlock(project.Span, &project.Trivia)
+		node.addElement(&project.DatabaseTypeElement)
+		node.addElement(&project.NoteElement)
+		for i := range project.SettingElements {
+			node.add(project.SettingElements[i].Span, &project.SettingElements[i].Trivia)
+		}
+	}
+	for i := range dbml.Tables {
+		table := &dbml.Tables[i]
+		node := root.addBlock(table.Span, &table.Trivia)
+		node.addTableBody(table.Columns, table.Indexes, table.Checks, &table.IndexesElement, &table.ChecksElement)
+		node.addElement(&table.NoteElement)
+		for j := range table.Partials {
+			node.add(table.Partials[j].Span, &table.Partials[j].Trivia)
+		}
+	}
+	for i := range dbml.TablePartials {
+		partial := &dbml.TablePartials[i]
+		node := root.addBlock(partial.Span, &partial.Trivia)
+		node.addTableBody(partial.Columns, partial.Indexes, partial.Checks, &partial.IndexesElement, &partial.ChecksElement)
+		node.addElement(&partial.NoteElement)
+	}
+	for i := range dbml.Enums {
+		enum := &dbml.Enums[i]
+		node := root.addBlock(enum.Span, &enum.Trivia)
+		for j := range enum.Values {
+			node.add(enum.Values[j].Span, &enum.Values[j].Trivia)
+		}
+	}
+	for i := range dbml.Refs {
+		ref := &dbml.Refs[i]
+		node := root.addBlock(ref.Span, &ref.Trivia)
+		for j := range ref.Relationships {
+			node.add(ref.Relationships[j].Span, &ref.Relationships[j].Trivia)
+		}
+	}
+	for i := range dbml.TableGroups {
+		group := &dbml.TableGroups[i]
+		node := root.addBlock(group.Span, &group.Trivia)
+		for j := range group.Members {
+			node.add(group.Members[j].Span, &group.Members[j].Trivia)
+		}
+		node.addElement(&group.NoteElement)
+	}
+	for i := range dbml.Notes {
+		note := &dbml.Notes[i]
+		node := root.addBlock(note.Span, &note.Trivia)
+		node.addElement(&note.ContentElement)
+	}
+
+	root.sort()
+	return root
+}
+
+func (n *triviaNode) add(span core.Span, trivia *core.Trivia) *triviaNode {
+	child := &triviaNode{span: span, trivia: trivia}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *triviaNode) addBlock(span core.Span, trivia *core.Trivia) *triviaNode {
+	child := n.add(span, trivia)
+	child.block = true
+	return child
+}
+
+// addElement adds element found in body, elements of absent values have no position.
+func (n *triviaNode) addElement(element *core.Element) {
+	if element.Span != (core.Span{}) {
+		n.add(element.Span, &element.Trivia)
+	}
+}
+
+func (n *triviaNode) addTableBody(
+	columns []core.Column,
+	indexes []core.Index,
+	checks []core.Check,
+	indexesElement, checksElement *core.Element,
+) {
+	for i := range columns {
+		n.add(columns[i].Span, &columns[i].Trivia)
+	}
+
+	indexesNode := n
+	if indexesElement.Span != (core.Span{}) {
+		indexesNode = n.addBlock(indexesElement.Span, &indexesElement.Trivia)
+	}
+	for i := range indexes {
+		indexesNode.add(indexes[i].Span, &indexes[i].Trivia)
+	}
+
+	checksNode := n
+	if checksElement.Span != (core.Span{}) {
+		checksNode = n.addBlock(checksElement.Span, &checksElement.Trivia)
+	}
+	for i := range checks {
+		checksNode.add(checks[i].Span, &checks[i].Trivia)
+	}
+}
+
+func (n *triviaNode) sort() {
+	sort.SliceStable(n.children, func(i, j int) bool {
+		return n.children[i].span.Start.Offset < n.children[j].span.Start.Offset
+	})
+	for _, child := range n.children {
+		child.sort()
+	}
+}
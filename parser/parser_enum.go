@@ -1,20 +1,26 @@
 package parser
 
 import (
-	"fmt"
-
 	"github.com/artarts36/dbml-go/core"
 	"github.com/artarts36/dbml-go/token"
 )
 
 func (p *Parser) parseEnum() (*core.Enum, error) {
 	enum := &core.Enum{}
+	start := p.pos
+	defer p.enter("enum")()
 	p.next()
 
 	if !token.IsIdent(p.token) && p.token != token.DSTRING {
-		return nil, fmt.Errorf("enum name is invalid: %s", p.lit)
+		return nil, p.errorf("enum name is invalid: %s", p.lit)
+	}
+	schema, name, err := p.parseQualifiedName()
+	if err != nil {
+		return nil, err
 	}
-	enum.Name = p.lit
+	enum.Schema = schema
+	enum.Name = name
+	p.rename("enum " + core.QualifiedName(schema, name))
 	p.next()
 	if p.token != token.LBRACE {
 		return nil, p.expect("{")
@@ -25,6 +31,7 @@ func (p *Parser) parseEnum() (*core.Enum, error) {
 		enumValue := core.EnumValue{
 			Name: p.lit,
 		}
+		valueStart := p.pos
 		p.next()
 		if p.token == token.LBRACK {
 			// handle [Note: ...]
@@ -35,6 +42,7 @@ func (p *Parser) parseEnum() (*core.Enum, error) {
 					return nil, p.expect("note: 'string'")
 				}
 				enumValue.Note = note
+				enumValue.NoteRaw = p.raw()
 				p.next()
 			}
 			if p.token != token.RBRACK {
@@ -42,11 +50,13 @@ func (p *Parser) parseEnum() (*core.Enum, error) {
 			}
 			p.next()
 		}
+		enumValue.Span = core.Span{Start: valueStart, End: p.prevEnd}
 		enum.Values = append(enum.Values, enumValue)
 	}
 
 	if p.token != token.RBRACE {
 		return nil, p.expect("}")
 	}
+	enum.Span = p.span(start)
 	return enum, nil
 }
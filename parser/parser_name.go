@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"github.com/artarts36/dbml-go/token"
+)
+
+// parseQualifiedName parses name with optional schema: name, schema.name or "schema"."name".
+// Current token must be the first part of name, returned schema is empty when it is not specified.
+func (p *Parser) parseQualifiedName() (schema, name string, err error) {
+	parts, err := p.parseNameParts()
+	if err != nil {
+		return "", "", err
+	}
+
+	switch len(parts) {
+	case 1:
+		return "", parts[0], nil
+	case 2:
+		return parts[0], parts[1], nil
+	default:
+		return "", "", p.expect("name", "schema.name")
+	}
+}
+
+// parseNameParts parses dot separated name parts, current token must be the first part.
+func (p *Parser) parseNameParts() ([]string, error) {
+	parts := []string{p.lit}
+	for p.peek() == token.PERIOD {
+		p.next()
+		p.next()
+		if !isName(p.token) {
+			return nil, p.expect("name")
+		}
+		parts = append(parts, p.lit)
+	}
+	return parts, nil
+}
+
+func isName(t token.Token) bool {
+	return token.IsIdent(t) || t == token.DSTRING
+}
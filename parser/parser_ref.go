@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/token"
+)
+
+// parseReferentialActions parses [delete: ..., update: ...], current token must be '['.
+func (p *Parser) parseReferentialActions(onDelete, onUpdate *core.ReferentialAction) error {
+	commaAllowed := false
+
+	for {
+		p.next()
+		switch p.token {
+		case token.DELETE, token.UPDATE:
+			target := onDelete
+			if p.token == token.UPDATE {
+				target = onUpdate
+			}
+			p.next()
+			if p.token != token.COLON {
+				return p.expect(":")
+			}
+			p.next()
+			action, err := p.parseReferentialAction()
+			if err != nil {
+				return err
+			}
+			*target = action
+		case token.COMMA:
+			if !commaAllowed {
+				return p.expect("delete", "update")
+			}
+		case token.RBRACK:
+			return nil
+		default:
+			return p.expect("delete", "update")
+		}
+		commaAllowed = !commaAllowed
+	}
+}
+
+func (p *Parser) parseReferentialAction() (core.ReferentialAction, error) {
+	switch {
+	case p.token == token.IDENT && strings.ToLower(p.lit) == "cascade":
+		return core.ReferentialActionCascade, nil
+	case p.token == token.RESTRICT:
+		return core.ReferentialActionRestrict, nil
+	case p.token == token.SET:
+		p.next()
+		switch p.token {
+		case token.NULL:
+			return core.ReferentialActionSetNull, nil
+		case token.DEFAULT:
+			return core.ReferentialActionSetDefault, nil
+		default:
+			return core.ReferentialActionNone, p.expect("null", "default")
+		}
+	case p.token == token.NO:
+		p.next()
+		if p.token != token.ACTION {
+			return core.ReferentialActionNone, p.expect("action")
+		}
+		return core.ReferentialActionNoAction, nil
+	default:
+		return core.ReferentialActionNone, p.expect("cascade", "restrict", "set null", "set default", "no action")
+	}
+}
+
+// parseRelationshipEndpoint parses [schema.]table.column or composite [schema.]table.(column1, column2).
+func (p *Parser) parseRelationshipEndpoint() (*core.RelationshipEndpoint, error) {
+	parts := []string{p.lit}
+	composite := false
+	for !composite && p.peek() == token.PERIOD {
+		p.next()
+		p.next()
+		switch {
+		case p.token == token.LPAREN:
+			composite = true
+		case isName(p.token):
+			parts = append(parts, p.lit)
+		default:
+			return nil, p.expect("column_name", "(column_name, ...)")
+		}
+	}
+
+	endpoint := &core.RelationshipEndpoint{}
+	if composite {
+		columns, err := p.parseCompositeColumns()
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Columns = columns
+	} else {
+		endpoint.Columns = parts[len(parts)-1:]
+		parts = parts[:len(parts)-1]
+	}
+
+	switch len(parts) {
+	case 1:
+		endpoint.Table = parts[0]
+	case 2:
+		endpoint.Schema, endpoint.Table = parts[0], parts[1]
+	default:
+		return nil, p.expect("table.column_name", "schema.table.column_name")
+	}
+	return endpoint, nil
+}
+
+// parseCompositeColumns parses column list of composite key, current token must be '('.
+func (p *Parser) parseCompositeColumns() ([]string, error) {
+	var columns []string
+	for {
+		p.next()
+		if !isName(p.token) {
+			return nil, p.expect("column_name")
+		}
+		columns = append(columns, p.lit)
+		p.next()
+		switch p.token {
+		case token.COMMA:
+		case token.RPAREN:
+			return columns, nil
+		default:
+			return nil, p.expect(",", ")")
+		}
+	}
+}
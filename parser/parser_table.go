@@ -1,7 +1,8 @@
 package parser
 
 import (
-	"fmt"
+	"context"
+	"strings"
 
 	"github.com/artarts36/dbml-go/core"
 	"github.com/artarts36/dbml-go/token"
@@ -13,32 +14,120 @@ func (p *Parser) parseTableSettings() (*core.TableSettings, error) {
 
 	for {
 		p.next()
-		switch p.token {
-		case token.HEADERCOLOR:
+		switch {
+		case p.token == token.HEADERCOLOR:
 			p.next()
 			if p.token != token.COLON {
 				return nil, p.expect(":")
 			}
 			p.next()
-			if p.lit != "#" {
-				return nil, p.expect("#")
+			if p.token != token.COLOR {
+				return nil, p.expect("#color")
 			}
-			p.next()
 
-			if p.token != token.IDENT {
-				return nil, p.expect("color string")
+			tableSetting.HeaderColor = p.lit
+		case p.token == token.NOTE:
+			note, err := p.parseDescription()
+			if err != nil {
+				return nil, err
 			}
-
-			tableSetting.HeaderColor = fmt.Sprintf("#%s", p.lit)
-		case token.COMMA:
+			tableSetting.Note = note
+			tableSetting.NoteRaw = p.raw()
+		case p.token == token.COMMA:
 			if !commaAllowed {
-				return nil, p.expect("pk | primary key | unique")
+				return nil, p.expect("headercolor", "note", "setting_name")
 			}
-		case token.RBRACK:
+		case p.token == token.RBRACK:
 			return tableSetting, nil
+		case isName(p.token):
+			element, value, err := p.parseSetting()
+			if err != nil {
+				return nil, err
+			}
+			if tableSetting.Extra == nil {
+				tableSetting.Extra = map[string]string{}
+			}
+			tableSetting.Extra[strings.ToLower(element.Name)] = value
+			tableSetting.ExtraElements = appendSetting(tableSetting.ExtraElements, *element)
 		default:
-			return nil, p.expect("pk, primary key, unique")
+			return nil, p.expect("headercolor", "note", "setting_name")
 		}
 		commaAllowed = !commaAllowed
 	}
 }
+
+// parseSetting parses unknown setting: name, name: value. Current token must be setting name.
+// Value of string is its content, value of other literal is literal as written, value of flag is empty.
+func (p *Parser) parseSetting() (*core.SettingElement, string, error) {
+	start := p.pos
+	element := &core.SettingElement{Name: p.lit, Span: p.span(start)}
+	if p.peek() != token.COLON {
+		return element, "", nil
+	}
+	p.next()
+	p.next()
+	switch {
+	case p.token == token.STRING, p.token == token.DSTRING, p.token == token.TSTRING,
+		p.token == token.COLOR, p.token == token.INT, p.token == token.FLOAT, p.token == token.EXPR,
+		token.IsIdent(p.token):
+		element.Raw = p.raw()
+		element.Span = p.span(start)
+		return element, p.lit, nil
+	default:
+		return nil, "", p.expect("setting value")
+	}
+}
+
+// appendSetting appends declaration of setting, previous declaration of the same setting is removed
+// because its value is overwritten.
+func appendSetting(elements []core.SettingElement, element core.SettingElement) []core.SettingElement {
+	for i := range elements {
+		if strings.EqualFold(elements[i].Name, element.Name) {
+			elements = append(elements[:i], elements[i+1:]...)
+			break
+		}
+	}
+	return append(elements, element)
+}
+
+func (p *Parser) parseTablePartial(ctx context.Context) (*core.TablePartial, error) {
+	start := p.pos
+	p.next()
+	if !isName(p.token) {
+		return nil, p.expect("partial_name")
+	}
+	partial := &core.TablePartial{
+		Name: p.lit,
+	}
+	defer p.enter("table partial " + partial.Name)()
+
+	p.next()
+	if p.token == token.LBRACK {
+		tableSetting, err := p.parseTableSettings()
+		if err != nil {
+			return nil, err
+		}
+		p.next() // remove ']'
+		partial.Settings = *tableSetting
+	}
+
+	if p.token != token.LBRACE {
+		return nil, p.expect("{")
+	}
+
+	body := &core.Table{}
+	if err := p.parseTableBody(ctx, body, false); err != nil {
+		return nil, err
+	}
+	partial.Note = body.Note
+	partial.NoteRaw = body.NoteRaw
+	partial.Columns = body.Columns
+	partial.Indexes = body.Indexes
+	partial.Checks = body.Checks
+	partial.NoteElement = body.NoteElement
+	partial.IndexesElement = body.IndexesElement
+	partial.ChecksElement = body.ChecksElement
+	partial.Span = p.span(start)
+
+	return partial, nil
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"context"
+	"errors"
 	"github.com/artarts36/dbml-go/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/artarts36/dbml-go/scanner"
+	"github.com/artarts36/dbml-go/token"
 )
 
 func p(str string) *Parser {
@@ -18,6 +20,10 @@ func p(str string) *Parser {
 	return parser
 }
 
+func pos(offset, line, column int) token.Position {
+	return token.Position{Offset: offset, Line: line, Column: column}
+}
+
 func TestIllegalSyntax(t *testing.T) {
 	parser := p(`Project test { abc , xyz`)
 	_, err := parser.Parse(context.Background())
@@ -64,6 +70,60 @@ func TestParseSimple(t *testing.T) {
 	}
 }
 
+func TestParser_Parse_Project_Settings(t *testing.T) {
+	dbml, err := p(`
+	Project shop {
+		database_type: 'PostgreSQL'
+		version: '1.2'
+		Owner: "billing team"
+		sql_dialect_version: 16
+		note: 'shop database'
+	}
+	`).Parse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "PostgreSQL", dbml.Project.DatabaseType)
+	assert.Equal(t, "shop database", dbml.Project.Note)
+	assert.Equal(t, []core.SettingElement{
+		{
+			Name: "version",
+			Raw:  "'1.2'",
+			Span: core.Span{Start: pos(49, 4, 3), End: pos(63, 4, 17)},
+		},
+		{
+			Name: "Owner",
+			Raw:  `"billing team"`,
+			Span: core.Span{Start: pos(66, 5, 3), End: pos(87, 5, 24)},
+		},
+		{
+			Name: "sql_dialect_version",
+			Raw:  "16",
+			Span: core.Span{Start: pos(90, 6, 3), End: pos(113, 6, 26)},
+		},
+	}, dbml.Project.SettingElements)
+	assert.Equal(t, map[string]string{
+		"version":             "1.2",
+		"owner":               "billing team",
+		"sql_dialect_version": "16",
+	}, dbml.Project.Settings)
+
+	_, err = p(`Project shop { version '1.2' }`).Parse(context.Background())
+	require.EqualError(t, err, "[1:24] project shop: invalid token '1.2' determined as STRING, expected: ':'")
+
+	dbml, err = p(`Project { version: '1.2' }`).Parse(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "", dbml.Project.Name)
+	assert.Equal(t, map[string]string{"version": "1.2"}, dbml.Project.Settings)
+
+	dbml, err = p(`Project { Database_Type: 'pg' }`).Parse(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pg", dbml.Project.DatabaseType)
+	assert.Empty(t, dbml.Project.Settings)
+
+	_, err = p(`Project 'shop' {}`).Parse(context.Background())
+	require.EqualError(t, err, "[1:9] invalid token 'shop' determined as STRING, expected: 'project_name | {'")
+}
+
 func TestParseTableName(t *testing.T) {
 	parser := p(`
 	Table int {
@@ -134,6 +194,36 @@ func TestAllowKeywordsAsTable(t *testing.T) {
 	}
 }
 
+func TestAllowKeywordsAsRefTable(t *testing.T) {
+	parser := p(`
+	Table project {
+		id int
+		owner_id int [ref: > note.id]
+	}
+	Table note {
+		id int
+	}
+	Table users {
+		pid int [ref: > project.id]
+	}
+	Ref: users.pid > project.id
+	Ref: note.id > users.pid
+	Ref {
+		project.owner_id > note.id
+	}
+	`)
+	dbml, err := parser.Parse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "note", dbml.Tables[0].Columns[1].Settings.Ref.To.Table)
+	assert.Equal(t, "project", dbml.Tables[2].Columns[0].Settings.Ref.To.Table)
+	require.Len(t, dbml.Refs, 3)
+	assert.Equal(t, "project", dbml.Refs[0].Relationships[0].To.Table)
+	assert.Equal(t, "note", dbml.Refs[1].Relationships[0].From.Table)
+	assert.Equal(t, "project", dbml.Refs[2].Relationships[0].From.Table)
+	assert.Equal(t, "note", dbml.Refs[2].Relationships[0].To.Table)
+}
+
 func TestParser_Parse_Enum(t *testing.T) {
 	cases := []struct {
 		Title    string
@@ -152,8 +242,10 @@ func TestParser_Parse_Enum(t *testing.T) {
 				Values: []core.EnumValue{
 					{
 						Name: "key",
+						Span: core.Span{Start: pos(19, 3, 3), End: pos(22, 3, 6)},
 					},
 				},
+				Span: core.Span{Start: pos(2, 2, 2), End: pos(25, 4, 3)},
 			},
 		},
 		{
@@ -168,8 +260,10 @@ func TestParser_Parse_Enum(t *testing.T) {
 				Values: []core.EnumValue{
 					{
 						Name: "key a b",
+						Span: core.Span{Start: pos(19, 3, 3), End: pos(28, 3, 12)},
 					},
 				},
+				Span: core.Span{Start: pos(2, 2, 2), End: pos(31, 4, 3)},
 			},
 		},
 	}
@@ -200,9 +294,10 @@ func TestParser_Parse_Column_Settings_Default(t *testing.T) {
 	}
 `,
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeString,
-				Raw:   "test",
-				Value: "test",
+				Type:   core.ColumnDefaultTypeString,
+				Raw:    "test",
+				Value:  "test",
+				Source: "'test'",
 			},
 		},
 		{
@@ -213,9 +308,10 @@ func TestParser_Parse_Column_Settings_Default(t *testing.T) {
 	}
 `,
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeString,
-				Raw:   "test",
-				Value: "test",
+				Type:   core.ColumnDefaultTypeString,
+				Raw:    "test",
+				Value:  "test",
+				Source: `"test"`,
 			},
 		},
 		{
@@ -226,9 +322,10 @@ func TestParser_Parse_Column_Settings_Default(t *testing.T) {
 	}
 `,
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeNumber,
-				Raw:   "123",
-				Value: 123,
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "123",
+				Value:  123,
+				Source: "123",
 			},
 		},
 		{
@@ -239,9 +336,10 @@ func TestParser_Parse_Column_Settings_Default(t *testing.T) {
 	}
 `,
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeNumber,
-				Raw:   "123",
-				Value: 123,
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "123",
+				Value:  123,
+				Source: "123",
 			},
 		},
 		{
@@ -252,45 +350,110 @@ func TestParser_Parse_Column_Settings_Default(t *testing.T) {
 	}
 `,
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeNumber,
-				Raw:   "123.456",
-				Value: 123.456,
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "123.456",
+				Value:  123.456,
+				Source: "123.456",
+			},
+		},
+		{
+			Title: "parse negative int value",
+			Spec:  "Table user { balance int [default: -1]}",
+			Expected: core.ColumnDefault{
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "-1",
+				Value:  -1,
+				Source: "-1",
+			},
+		},
+		{
+			Title: "parse positive signed int value",
+			Spec:  "Table user { balance int [default: +15]}",
+			Expected: core.ColumnDefault{
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "+15",
+				Value:  15,
+				Source: "+15",
+			},
+		},
+		{
+			Title: "parse negative float value",
+			Spec:  "Table user { balance float [default: - 0.1]}",
+			Expected: core.ColumnDefault{
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "-0.1",
+				Value:  -0.1,
+				Source: "- 0.1",
+			},
+		},
+		{
+			Title: "parse float value in scientific notation",
+			Spec:  "Table user { balance float [default: 1.5e-3]}",
+			Expected: core.ColumnDefault{
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "1.5e-3",
+				Value:  0.0015,
+				Source: "1.5e-3",
+			},
+		},
+		{
+			Title: "parse negative value in scientific notation",
+			Spec:  "Table user { balance float [default: -2E10]}",
+			Expected: core.ColumnDefault{
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "-2E10",
+				Value:  -2e10,
+				Source: "-2E10",
+			},
+		},
+		{
+			Title: "parse float value with full precision",
+			Spec:  "Table user { balance float [default: 0.1234567890123]}",
+			Expected: core.ColumnDefault{
+				Type:   core.ColumnDefaultTypeNumber,
+				Raw:    "0.1234567890123",
+				Value:  0.1234567890123,
+				Source: "0.1234567890123",
 			},
 		},
 		{
 			Title: "parse expression value",
 			Spec:  "Table user { name varchar [default: `now()`]}",
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeExpression,
-				Raw:   "now()",
-				Value: "now()",
+				Type:   core.ColumnDefaultTypeExpression,
+				Raw:    "now()",
+				Value:  "now()",
+				Source: "`now()`",
 			},
 		},
 		{
 			Title: "parse false value",
 			Spec:  "Table user { name varchar [default: false]}",
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeBoolean,
-				Raw:   "false",
-				Value: false,
+				Type:   core.ColumnDefaultTypeBoolean,
+				Raw:    "false",
+				Value:  false,
+				Source: "false",
 			},
 		},
 		{
 			Title: "parse true value",
 			Spec:  "Table user { name varchar [default: true]}",
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeBoolean,
-				Raw:   "true",
-				Value: true,
+				Type:   core.ColumnDefaultTypeBoolean,
+				Raw:    "true",
+				Value:  true,
+				Source: "true",
 			},
 		},
 		{
 			Title: "parse null value",
 			Spec:  "Table user { name varchar [default: null]}",
 			Expected: core.ColumnDefault{
-				Type:  core.ColumnDefaultTypeBoolean,
-				Raw:   "null",
-				Value: nil,
+				Type:   core.ColumnDefaultTypeNull,
+				Raw:    "null",
+				Value:  nil,
+				Source: "null",
 			},
 		},
 	}
@@ -304,3 +467,1101 @@ func TestParser_Parse_Column_Settings_Default(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_Parse_Ref_ReferentialActions(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected core.Relationship
+	}{
+		{
+			Title: "parse short ref with actions",
+			Spec:  `Ref: posts.user_id > users.id [delete: cascade, update: set null]`,
+			Expected: core.Relationship{
+				From:     core.RelationshipEndpoint{Table: "posts", Columns: []string{"user_id"}},
+				To:       core.RelationshipEndpoint{Table: "users", Columns: []string{"id"}},
+				Type:     core.ManyToOne,
+				OnDelete: core.ReferentialActionCascade,
+				OnUpdate: core.ReferentialActionSetNull,
+				Span:     core.Span{Start: pos(5, 1, 6), End: pos(65, 1, 66)},
+			},
+		},
+		{
+			Title: "parse long ref with actions",
+			Spec: `
+	Ref name {
+		posts.user_id > users.id [update: no action, delete: set default]
+	}
+`,
+			Expected: core.Relationship{
+				From:     core.RelationshipEndpoint{Table: "posts", Columns: []string{"user_id"}},
+				To:       core.RelationshipEndpoint{Table: "users", Columns: []string{"id"}},
+				Type:     core.ManyToOne,
+				OnDelete: core.ReferentialActionSetDefault,
+				OnUpdate: core.ReferentialActionNoAction,
+				Span:     core.Span{Start: pos(15, 3, 3), End: pos(80, 3, 68)},
+			},
+		},
+		{
+			Title: "parse ref without actions",
+			Spec:  `Ref: posts.user_id - users.id`,
+			Expected: core.Relationship{
+				From: core.RelationshipEndpoint{Table: "posts", Columns: []string{"user_id"}},
+				To:   core.RelationshipEndpoint{Table: "users", Columns: []string{"id"}},
+				Type: core.OneToOne,
+				Span: core.Span{Start: pos(5, 1, 6), End: pos(29, 1, 30)},
+			},
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.Title, func(t *testing.T) {
+			dbml, err := p(tCase.Spec).Parse(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tCase.Expected, dbml.Refs[0].Relationships[0])
+		})
+	}
+}
+
+func TestParser_Parse_Ref_ManyToMany(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected core.Relationship
+	}{
+		{
+			Title: "parse many to many ref",
+			Spec:  `Ref: authors.id <> books.id`,
+			Expected: core.Relationship{
+				From: core.RelationshipEndpoint{Table: "authors", Columns: []string{"id"}},
+				To:   core.RelationshipEndpoint{Table: "books", Columns: []string{"id"}},
+				Type: core.ManyToMany,
+				Span: core.Span{Start: pos(5, 1, 6), End: pos(27, 1, 28)},
+			},
+		},
+		{
+			Title: "parse many to many ref without spaces",
+			Spec:  `Ref { authors.id<>books.id }`,
+			Expected: core.Relationship{
+				From: core.RelationshipEndpoint{Table: "authors", Columns: []string{"id"}},
+				To:   core.RelationshipEndpoint{Table: "books", Columns: []string{"id"}},
+				Type: core.ManyToMany,
+				Span: core.Span{Start: pos(6, 1, 7), End: pos(26, 1, 27)},
+			},
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.Title, func(t *testing.T) {
+			dbml, err := p(tCase.Spec).Parse(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tCase.Expected, dbml.Refs[0].Relationships[0])
+		})
+	}
+}
+
+func TestParser_Parse_Ref_Composite(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected core.Relationship
+	}{
+		{
+			Title: "parse composite ref",
+			Spec:  `Ref: merchant_periods.(merchant_id, country_code) > merchants.(id, country_code)`,
+			Expected: core.Relationship{
+				From: core.RelationshipEndpoint{Table: "merchant_periods", Columns: []string{"merchant_id", "country_code"}},
+				To:   core.RelationshipEndpoint{Table: "merchants", Columns: []string{"id", "country_code"}},
+				Type: core.ManyToOne,
+				Span: core.Span{Start: pos(5, 1, 6), End: pos(80, 1, 81)},
+			},
+		},
+		{
+			Title: "parse composite ref in long form",
+			Spec: `
+	Ref {
+		merchant_periods.(merchant_id, country_code) > merchants.(id, country_code) [delete: cascade]
+		merchant_periods.merchant_id > merchants.id
+	}
+`,
+			Expected: core.Relationship{
+				From:     core.RelationshipEndpoint{Table: "merchant_periods", Columns: []string{"merchant_id", "country_code"}},
+				To:       core.RelationshipEndpoint{Table: "merchants", Columns: []string{"id", "country_code"}},
+				Type:     core.ManyToOne,
+				OnDelete: core.ReferentialActionCascade,
+				Span:     core.Span{Start: pos(10, 3, 3), End: pos(103, 3, 96)},
+			},
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.Title, func(t *testing.T) {
+			dbml, err := p(tCase.Spec).Parse(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tCase.Expected, dbml.Refs[0].Relationships[0])
+		})
+	}
+}
+
+func TestParser_Parse_Column_Settings_RefReferentialActions(t *testing.T) {
+	dbml, err := p(`
+	Table posts {
+		user_id int [ref: > users.id [delete: restrict], not null]
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	ref := dbml.Tables[0].Columns[0].Settings.Ref
+	assert.Equal(t, core.RelationshipType(core.ManyToOne), ref.Type)
+	assert.Equal(t, core.RelationshipEndpoint{Table: "users", Columns: []string{"id"}}, ref.To)
+	assert.Equal(t, core.ReferentialActionRestrict, ref.OnDelete)
+	assert.Equal(t, core.ReferentialActionNone, ref.OnUpdate)
+}
+
+func TestParser_Parse_Column_Settings_RefManyToMany(t *testing.T) {
+	dbml, err := p(`
+	Table authors {
+		book_id int [ref: <> books.id]
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	ref := dbml.Tables[0].Columns[0].Settings.Ref
+	assert.Equal(t, core.RelationshipType(core.ManyToMany), ref.Type)
+	assert.Equal(t, core.RelationshipEndpoint{Table: "books", Columns: []string{"id"}}, ref.To)
+}
+
+func TestParser_Parse_SchemaQualifiedNames(t *testing.T) {
+	dbml, err := p(`
+	Table billing.invoices {
+		id int [pk]
+		status billing.invoice_status
+		user_id int [ref: > "auth"."users".id]
+	}
+	Table "billing"."invoice lines" as L {
+		invoice_id int
+	}
+	Enum billing.invoice_status {
+		paid
+	}
+	Ref: billing."invoice lines".(invoice_id) > billing.invoices.(id)
+	TableGroup billing {
+		billing.invoices
+		"billing"."invoice lines"
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, dbml.Tables, 2)
+	assert.Equal(t, "billing", dbml.Tables[0].Schema)
+	assert.Equal(t, "invoices", dbml.Tables[0].Name)
+	assert.Equal(t, "billing.invoice_status", dbml.Tables[0].Columns[1].Type)
+	assert.Equal(t, core.RelationshipEndpoint{
+		Schema:  "auth",
+		Table:   "users",
+		Columns: []string{"id"},
+	}, dbml.Tables[0].Columns[2].Settings.Ref.To)
+
+	assert.Equal(t, "billing", dbml.Tables[1].Schema)
+	assert.Equal(t, "invoice lines", dbml.Tables[1].Name)
+	assert.Equal(t, "L", dbml.Tables[1].As)
+
+	assert.Equal(t, "billing", dbml.Enums[0].Schema)
+	assert.Equal(t, "invoice_status", dbml.Enums[0].Name)
+
+	assert.Equal(t, core.Relationship{
+		From: core.RelationshipEndpoint{
+			Schema:  "billing",
+			Table:   "invoice lines",
+			Columns: []string{"invoice_id"},
+		},
+		To:   core.RelationshipEndpoint{Schema: "billing", Table: "invoices", Columns: []string{"id"}},
+		Type: core.ManyToOne,
+		Span: core.Span{Start: pos(224, 13, 7), End: pos(284, 13, 67)},
+	}, dbml.Refs[0].Relationships[0])
+
+	assert.Equal(t, []core.TableGroupMember{
+		{
+			Schema: "billing",
+			Name:   "invoices",
+			Span:   core.Span{Start: pos(309, 15, 3), End: pos(325, 15, 19)},
+		},
+		{
+			Schema: "billing",
+			Name:   "invoice lines",
+			Span:   core.Span{Start: pos(328, 16, 3), End: pos(353, 16, 28)},
+		},
+	}, dbml.TableGroups[0].Members)
+}
+
+func TestParser_Parse_StickyNote(t *testing.T) {
+	dbml, err := p(`
+	Table users {
+		id int
+	}
+
+	Note single_line_note {
+		'This is a single line note'
+	}
+
+	Note "multiple lines" {
+'''
+# Title
+* item
+'''
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []core.StickyNote{
+		{
+			Name:       "single_line_note",
+			Content:    "This is a single line note",
+			ContentRaw: "'This is a single line note'",
+			ContentElement: core.Element{
+				Span: core.Span{Start: pos(56, 7, 3), End: pos(84, 7, 31)},
+			},
+			Span: core.Span{Start: pos(30, 6, 2), End: pos(87, 8, 3)},
+		},
+		{
+			Name:       "multiple lines",
+			Content:    "# Title\n* item",
+			ContentRaw: "'''\n# Title\n* item\n'''",
+			ContentElement: core.Element{
+				Span: core.Span{Start: pos(114, 11, 1), End: pos(136, 14, 4)},
+			},
+			Span: core.Span{Start: pos(90, 10, 2), End: pos(139, 15, 3)},
+		},
+	}, dbml.Notes)
+}
+
+func TestParser_Parse_TableGroup(t *testing.T) {
+	spec := `TableGroup billing [color: #3498DB, note: 'setting note'] {
+  billing.invoices
+  users
+  Note: '''
+    group note
+  '''
+}
+
+TableGroup empty {
+}`
+	dbml, err := p(spec).Parse(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, dbml.TableGroups, 2)
+	group := dbml.TableGroups[0]
+	assert.Equal(t, "billing", group.Name)
+	assert.Equal(t, "#3498DB", group.Color)
+	assert.Equal(t, "group note", group.Note)
+	assert.Equal(t, []core.TableGroupMember{
+		{Schema: "billing", Name: "invoices", Span: core.Span{Start: pos(62, 2, 3), End: pos(78, 2, 19)}},
+		{Name: "users", Span: core.Span{Start: pos(81, 3, 3), End: pos(86, 3, 8)}},
+	}, group.Members)
+	assert.Equal(t, "TableGroup billing", spec[group.Span.Start.Offset:group.Span.Start.Offset+18])
+	assert.Equal(t, 7, group.Span.End.Line)
+
+	assert.Empty(t, dbml.TableGroups[1].Members)
+
+	_, err = p(`TableGroup g [colour: #fff] {}`).Parse(context.Background())
+	require.EqualError(t, err, "[1:15] table group g: invalid token 'colour' determined as IDENT, expected: 'color | note'")
+}
+
+func TestParser_Parse_TablePartial(t *testing.T) {
+	dbml, err := p(`
+	TablePartial audit [headercolor: #fff] {
+		created_at timestamp [not null]
+		updated_at timestamp
+		note: 'audited'
+		indexes {
+			created_at
+		}
+	}
+
+	TablePartial soft_delete [note: 'soft deleted', headercolor: #3498DB, Retention: 30] {
+		deleted_at timestamp
+		updated_at datetime
+	}
+
+	Table users [retention: 90, archived] {
+		id int [pk]
+		~audit
+		name varchar
+		updated_at timestamptz
+		~soft_delete
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, dbml.TablePartials, 2)
+	audit := dbml.TablePartials[0]
+	assert.Equal(t, "audit", audit.Name)
+	assert.Equal(t, "audited", audit.Note)
+	assert.Equal(t, "#fff", audit.Settings.HeaderColor)
+	assert.Len(t, audit.Columns, 2)
+	assert.Len(t, audit.Indexes, 1)
+
+	assert.Equal(t, []core.TablePartialInjection{
+		{Name: "audit", Position: 1, Span: core.Span{Start: pos(346, 18, 3), End: pos(352, 18, 9)}},
+		{Name: "soft_delete", Position: 3, Span: core.Span{Start: pos(395, 21, 3), End: pos(407, 21, 15)}},
+	}, dbml.Tables[0].Partials)
+
+	resolved, err := dbml.ResolveTablePartials()
+	require.NoError(t, err)
+
+	users := resolved.Tables[0]
+	columns := make([]string, 0, len(users.Columns))
+	for _, column := range users.Columns {
+		columns = append(columns, column.Name+" "+column.Type)
+	}
+	assert.Equal(t, []string{
+		"id int",
+		"created_at timestamp",
+		"name varchar",
+		"updated_at timestamptz",
+		"deleted_at timestamp",
+	}, columns)
+	assert.Len(t, users.Indexes, 1)
+	assert.Equal(t, "audited", users.Note)
+	assert.Equal(t, core.TableSettings{
+		HeaderColor: "#3498DB",
+		Note:        "soft deleted",
+		NoteRaw:     "'soft deleted'",
+		Extra:       map[string]string{"retention": "90", "archived": ""},
+		ExtraElements: []core.SettingElement{
+			{Name: "retention", Raw: "90", Span: core.Span{Start: pos(303, 16, 15), End: pos(316, 16, 28)}},
+			{Name: "archived", Span: core.Span{Start: pos(318, 16, 30), End: pos(326, 16, 38)}},
+		},
+	}, users.Settings)
+	assert.Empty(t, users.Partials)
+
+	// source DBML must stay untouched
+	assert.Len(t, dbml.Tables[0].Columns, 3)
+}
+
+func TestParser_Parse_TableSettings(t *testing.T) {
+	dbml, err := p(`
+	Table users [headercolor: #3498db, note: 'users table', Owner: "billing", priority: 1, kind: main, audited, since: '2024', border: #fff] {
+		id int
+	}
+	Table posts [headercolor: #1a] {
+		id int
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, core.TableSettings{
+		HeaderColor: "#3498db",
+		Note:        "users table",
+		NoteRaw:     "'users table'",
+		Extra: map[string]string{
+			"owner": "billing", "priority": "1", "kind": "main", "audited": "", "since": "2024", "border": "#fff",
+		},
+		ExtraElements: []core.SettingElement{
+			{Name: "Owner", Raw: `"billing"`, Span: core.Span{Start: pos(58, 2, 58), End: pos(74, 2, 74)}},
+			{Name: "priority", Raw: "1", Span: core.Span{Start: pos(76, 2, 76), End: pos(87, 2, 87)}},
+			{Name: "kind", Raw: "main", Span: core.Span{Start: pos(89, 2, 89), End: pos(99, 2, 99)}},
+			{Name: "audited", Span: core.Span{Start: pos(101, 2, 101), End: pos(108, 2, 108)}},
+			{Name: "since", Raw: "'2024'", Span: core.Span{Start: pos(110, 2, 110), End: pos(123, 2, 123)}},
+			{Name: "border", Raw: "#fff", Span: core.Span{Start: pos(125, 2, 125), End: pos(137, 2, 137)}},
+		},
+	}, dbml.Tables[0].Settings)
+	assert.Equal(t, "#1a", dbml.Tables[1].Settings.HeaderColor)
+
+	dbml, err = p(`Table users [bar: 1, baz, Bar: 2] { id int }`).Parse(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"bar": "2", "baz": ""}, dbml.Tables[0].Settings.Extra)
+	assert.Equal(t, []core.SettingElement{
+		{Name: "baz", Span: core.Span{Start: pos(21, 1, 22), End: pos(24, 1, 25)}},
+		{Name: "Bar", Raw: "2", Span: core.Span{Start: pos(26, 1, 27), End: pos(32, 1, 33)}},
+	}, dbml.Tables[0].Settings.ExtraElements)
+
+	_, err = p(`Table users [headercolor: red] { id int }`).Parse(context.Background())
+	require.EqualError(t, err, "[1:27] table users: invalid token 'red' determined as IDENT, expected: '#color'")
+}
+
+func TestParser_Parse_TablePartial_NotFound(t *testing.T) {
+	dbml, err := p(`
+	Table users {
+		~audit
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	_, err = dbml.ResolveTablePartials()
+	require.Error(t, err)
+}
+
+func TestParser_Parse_RawStrings(t *testing.T) {
+	dbml, err := p(`
+	Project p {
+		Note: 'it\'s'
+	}
+	Table users [note: "say \"hi\""] {
+		name varchar [default: 'it\'s', note: '''
+		  multi
+		''']
+		Note: 'table\nnote'
+	}
+	Enum e {
+		a [note: 'a\\b']
+	}
+	TableGroup g {
+		users
+		Note: "group"
+	}
+`).Parse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "it's", dbml.Project.Note)
+	assert.Equal(t, `'it\'s'`, dbml.Project.NoteRaw)
+
+	users := dbml.Tables[0]
+	assert.Equal(t, `say "hi"`, users.Settings.Note)
+	assert.Equal(t, `"say \"hi\""`, users.Settings.NoteRaw)
+	assert.Equal(t, "table\nnote", users.Note)
+	assert.Equal(t, `'table\nnote'`, users.NoteRaw)
+
+	name := users.Columns[0].Settings
+	assert.Equal(t, "it's", name.Default.Raw)
+	assert.Equal(t, `'it\'s'`, name.Default.Source)
+	assert.Equal(t, "multi", name.Note)
+	assert.Equal(t, "'''\n\t\t  multi\n\t\t'''", name.NoteRaw)
+
+	assert.Equal(t, `a\b`, dbml.Enums[0].Values[0].Note)
+	assert.Equal(t, `'a\\b'`, dbml.Enums[0].Values[0].NoteRaw)
+	assert.Equal(t, `"group"`, dbml.TableGroups[0].NoteRaw)
+}
+
+func TestParser_Parse_Checks(t *testing.T) {
+	dbml, err := p("" +
+		"Table products {\n" +
+		"  price decimal [check: `price > 0`, check: `price < 1000000`, not null]\n" +
+		"  discount decimal\n" +
+		"  checks {\n" +
+		"    `discount < price` [name: 'chk_discount']\n" +
+		"    `discount >= 0`\n" +
+		"  }\n" +
+		"}\n" +
+		"TablePartial priced {\n" +
+		"  checks {\n" +
+		"    `price is not null`\n" +
+		"  }\n" +
+		"}\n",
+	).Parse(context.Background())
+	require.NoError(t, err)
+
+	products := dbml.Tables[0]
+	assert.Equal(t, []core.Check{
+		{Expression: "price > 0", Span: core.Span{Start: pos(34, 2, 18), End: pos(52, 2, 36)}},
+		{Expression: "price < 1000000", Span: core.Span{Start: pos(54, 2, 38), End: pos(78, 2, 62)}},
+	}, products.Columns[0].Settings.Checks)
+	assert.Len(t, products.Columns, 2)
+	assert.Equal(t, []core.Check{
+		{
+			Name:       "chk_discount",
+			Expression: "discount < price",
+			Span:       core.Span{Start: pos(124, 5, 5), End: pos(165, 5, 46)},
+		},
+		{Expression: "discount >= 0", Span: core.Span{Start: pos(170, 6, 5), End: pos(185, 6, 20)}},
+	}, products.Checks)
+	assert.Equal(t, core.Span{Start: pos(111, 4, 3), End: pos(189, 7, 4)}, products.ChecksElement.Span)
+	assert.Equal(t, []core.Check{
+		{Expression: "price is not null", Span: core.Span{Start: pos(229, 11, 5), End: pos(248, 11, 24)}},
+	}, dbml.TablePartials[0].Checks)
+	assert.Equal(t, core.Span{Start: pos(216, 10, 3), End: pos(252, 12, 4)}, dbml.TablePartials[0].ChecksElement.Span)
+}
+
+func TestParser_Parse_Index_Fields(t *testing.T) {
+	dbml, err := p("" +
+		"Table users {\n" +
+		"  id int\n" +
+		"  email varchar\n" +
+		"  indexes {\n" +
+		"    id\n" +
+		"    `lower(email)` [unique]\n" +
+		"    (`lower(email)`)\n" +
+		"    (id, `created_at desc`, \"email\") [name: 'idx_mixed']\n" +
+		"  }\n" +
+		"}\n",
+	).Parse(context.Background())
+	require.NoError(t, err)
+
+	column := func(name string) core.IndexField {
+		return core.IndexField{Value: name, Type: core.IndexFieldTypeColumn}
+	}
+	expression := func(expr string) core.IndexField {
+		return core.IndexField{Value: expr, Type: core.IndexFieldTypeExpression}
+	}
+
+	indexes := dbml.Tables[0].Indexes
+	require.Len(t, indexes, 4)
+	assert.Equal(t, []core.IndexField{column("id")}, indexes[0].Fields)
+	assert.Equal(t, []core.IndexField{expression("lower(email)")}, indexes[1].Fields)
+	assert.True(t, indexes[1].Settings.Unique)
+	assert.Equal(t, []core.IndexField{expression("lower(email)")}, indexes[2].Fields)
+	assert.Equal(t, []core.IndexField{
+		column("id"),
+		expression("created_at desc"),
+		column("email"),
+	}, indexes[3].Fields)
+	assert.Equal(t, "idx_mixed", indexes[3].Settings.Name)
+}
+
+func TestParser_Parse_Column_Type(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Type     string
+		Expected core.ColumnType
+	}{
+		{
+			Title:    "parse simple type",
+			Spec:     `Table t { c int [pk] }`,
+			Type:     "int",
+			Expected: core.ColumnType{Name: "int", Raw: "int"},
+		},
+		{
+			Title:    "parse type with single argument",
+			Spec:     `Table t { c varchar(255) }`,
+			Type:     "varchar(255)",
+			Expected: core.ColumnType{Name: "varchar", Args: []string{"255"}, Raw: "varchar(255)"},
+		},
+		{
+			Title:    "parse type with multiple arguments",
+			Spec:     `Table t { c decimal(10, 2) [not null] }`,
+			Type:     "decimal(10,2)",
+			Expected: core.ColumnType{Name: "decimal", Args: []string{"10", "2"}, Raw: "decimal(10, 2)"},
+		},
+		{
+			Title:    "parse type with identifier argument",
+			Spec:     `Table t { c varchar(max) }`,
+			Type:     "varchar(max)",
+			Expected: core.ColumnType{Name: "varchar", Args: []string{"max"}, Raw: "varchar(max)"},
+		},
+		{
+			Title:    "parse type with string arguments",
+			Spec:     `Table t { c enum('a', 'b') }`,
+			Type:     "enum('a','b')",
+			Expected: core.ColumnType{Name: "enum", Args: []string{"'a'", "'b'"}, Raw: "enum('a', 'b')"},
+		},
+		{
+			Title:    "parse type with escaped quotes in string arguments",
+			Spec:     `Table t { c enum('it''s', 'don\'t') }`,
+			Type:     `enum('it''s','don\'t')`,
+			Expected: core.ColumnType{Name: "enum", Args: []string{"'it''s'", `'don\'t'`}, Raw: `enum('it''s', 'don\'t')`},
+		},
+		{
+			Title:    "parse array type",
+			Spec:     `Table t { c int[] [not null] }`,
+			Type:     "int[]",
+			Expected: core.ColumnType{Name: "int", ArrayDimensions: 1, Raw: "int[]"},
+		},
+		{
+			Title:    "parse multidimensional array type with arguments",
+			Spec:     `Table t { c varchar(10)[][] }`,
+			Type:     "varchar(10)[][]",
+			Expected: core.ColumnType{Name: "varchar", Args: []string{"10"}, ArrayDimensions: 2, Raw: "varchar(10)[][]"},
+		},
+		{
+			Title:    "parse quoted type",
+			Spec:     `Table t { c "timestamp with time zone" }`,
+			Type:     "timestamp with time zone",
+			Expected: core.ColumnType{Name: "timestamp with time zone", Raw: `"timestamp with time zone"`},
+		},
+		{
+			Title:    "parse schema qualified type",
+			Spec:     `Table t { c billing.status[] }`,
+			Type:     "billing.status[]",
+			Expected: core.ColumnType{Schema: "billing", Name: "status", ArrayDimensions: 1, Raw: "billing.status[]"},
+		},
+		{
+			Title:    "parse type followed by empty settings",
+			Spec:     `Table t { c int [ ] }`,
+			Type:     "int",
+			Expected: core.ColumnType{Name: "int", Raw: "int"},
+		},
+		{
+			Title:    "parse array type followed by empty settings",
+			Spec:     `Table t { c int[] [ ] }`,
+			Type:     "int[]",
+			Expected: core.ColumnType{Name: "int", ArrayDimensions: 1, Raw: "int[]"},
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.Title, func(t *testing.T) {
+			dbml, err := p(tCase.Spec).Parse(context.Background())
+			require.NoError(t, err)
+
+			column := dbml.Tables[0].Columns[0]
+			assert.Equal(t, tCase.Expected, column.DataType)
+			assert.Equal(t, tCase.Type, column.Type)
+		})
+	}
+}
+
+func TestParser_Parse_Column_Settings_Null(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected core.Nullability
+	}{
+		{
+			Title:    "parse unspecified nullability",
+			Spec:     `Table t { c int [pk] }`,
+			Expected: core.NullabilityUnspecified,
+		},
+		{
+			Title:    "parse null",
+			Spec:     `Table t { c int [null, unique] }`,
+			Expected: core.NullabilityNullable,
+		},
+		{
+			Title:    "parse not null",
+			Spec:     `Table t { c int [unique, not null] }`,
+			Expected: core.NullabilityNotNull,
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.Title, func(t *testing.T) {
+			dbml, err := p(tCase.Spec).Parse(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tCase.Expected, dbml.Tables[0].Columns[0].Settings.Null)
+		})
+	}
+}
+
+func TestParser_Parse_Column_Settings_NullConflict(t *testing.T) {
+	_, err := p(`Table t { c int [null, not null] }`).Parse(context.Background())
+	require.EqualError(t, err, "[1:28] table t > column c: null and not null settings conflict")
+
+	_, err = p(`Table t { c int [not null, unique, null] }`).Parse(context.Background())
+	require.EqualError(t, err, "[1:36] table t > column c: null and not null settings conflict")
+
+	dbml, err := p(`Table t { c int [not null, not null] }`).Parse(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, core.NullabilityNotNull, dbml.Tables[0].Columns[0].Settings.Null)
+}
+
+func TestParser_Parse_Error(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected ParseError
+	}{
+		{
+			Title: "unexpected top level token",
+			Spec:  "Table users {\n  id int\n}\nfoo",
+			Expected: ParseError{
+				Position: token.Position{Offset: 25, Line: 4, Column: 1},
+				Token:    token.IDENT,
+				Literal:  "foo",
+				Expected: []string{"Project", "Ref", "Table", "TablePartial", "Enum", "TableGroup", "Note"},
+			},
+		},
+		{
+			Title: "invalid column setting",
+			Spec:  "Table users {\n  id int [pk, foo]\n}",
+			Expected: ParseError{
+				Position: token.Position{Offset: 28, Line: 2, Column: 15},
+				Token:    token.IDENT,
+				Literal:  "foo",
+				Expected: []string{"pk", "primary key", "null", "not null", "unique", "increment", "default", "note", "ref", "check"},
+				Context:  "table users > column id",
+			},
+		},
+		{
+			Title: "invalid table name",
+			Spec:  "\nTable { }",
+			Expected: ParseError{
+				Position: token.Position{Offset: 7, Line: 2, Column: 7},
+				Token:    token.LBRACE,
+				Literal:  "{",
+				Message:  "table name is invalid: {",
+				Context:  "table",
+			},
+		},
+		{
+			Title: "invalid qualified table name",
+			Spec:  "Table a.b.c {}",
+			Expected: ParseError{
+				Position: token.Position{Offset: 10, Line: 1, Column: 11},
+				Token:    token.IDENT,
+				Literal:  "c",
+				Expected: []string{"name", "schema.name"},
+				Context:  "table",
+			},
+		},
+		{
+			Title: "invalid qualified enum name",
+			Spec:  "Enum a.b.c {}",
+			Expected: ParseError{
+				Position: token.Position{Offset: 9, Line: 1, Column: 10},
+				Token:    token.IDENT,
+				Literal:  "c",
+				Expected: []string{"name", "schema.name"},
+				Context:  "enum",
+			},
+		},
+		{
+			Title: "invalid relationship",
+			Spec:  "Ref fk: posts.user_id >> users.id",
+			Expected: ParseError{
+				Position: token.Position{Offset: 23, Line: 1, Column: 24},
+				Token:    token.GTR,
+				Literal:  ">",
+				Expected: []string{"(rel to) table.column_name"},
+				Context:  "ref fk",
+			},
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.Title, func(t *testing.T) {
+			_, err := Parse(context.Background(), strings.NewReader(tCase.Spec))
+			require.Error(t, err)
+
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr))
+			assert.Equal(t, tCase.Expected, *parseErr)
+		})
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	err := &ParseError{
+		Position: token.Position{Offset: 28, Line: 2, Column: 15},
+		Token:    token.IDENT,
+		Literal:  "foo",
+		Expected: []string{"pk", "unique"},
+		Context:  "table users > column id",
+	}
+
+	assert.Equal(
+		t,
+		"[2:15] table users > column id: invalid token 'foo' determined as IDENT, expected: 'pk | unique'",
+		err.Error(),
+	)
+}
+
+func TestParser_Parse_AllErrors(t *testing.T) {
+	spec := `
+	Table users {
+		id int [pk, foo]
+		name varchar
+	}
+
+	Table posts {
+		id int [pk]
+		user_id int
+	}
+
+	foo bar
+
+	Enum status {
+		active [note: 1]
+	}
+
+	Ref: posts.user_id > users.id
+
+	Table broken
+	Table comments {
+		id int
+	}
+	`
+
+	dbml, err := ParseWithMode(context.Background(), strings.NewReader(spec), AllErrors)
+	require.Error(t, err)
+
+	var errs ErrorList
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 4)
+	assert.Equal(t, "table users > column id", errs[0].Context)
+	assert.Equal(t, "foo", errs[1].Literal)
+	assert.Equal(t, "enum status", errs[2].Context)
+	assert.Equal(t, "table broken", errs[3].Context)
+	assert.Equal(t, token.TABLE, errs[3].Token)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, errs[0], parseErr)
+	assert.True(t, errors.Is(err, errs[2]))
+
+	require.NotNil(t, dbml)
+	require.Len(t, dbml.Tables, 2)
+	assert.Equal(t, "posts", dbml.Tables[0].Name)
+	assert.Equal(t, "comments", dbml.Tables[1].Name)
+	assert.Len(t, dbml.Refs, 1)
+	assert.Empty(t, dbml.Enums)
+
+	// unclosed brace
+	dbml, err = ParseWithMode(context.Background(), strings.NewReader(`Table a {
+  id int [pk
+
+Table b {
+  id int
+}
+Table c {
+  id int
+}
+Enum e {
+  active
+}
+`), AllErrors)
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "table a > column id", errs[0].Context)
+
+	require.NotNil(t, dbml)
+	require.Len(t, dbml.Tables, 2)
+	assert.Equal(t, "b", dbml.Tables[0].Name)
+	assert.Equal(t, "c", dbml.Tables[1].Name)
+	require.Len(t, dbml.Enums, 1)
+	assert.Equal(t, "e", dbml.Enums[0].Name)
+}
+
+func TestParser_Parse_StopsOnFirstError(t *testing.T) {
+	dbml, err := Parse(context.Background(), strings.NewReader(`
+	Table users {
+		id int [pk, foo]
+	}
+	Table posts {
+		id int [foo]
+	}
+	`))
+	require.Error(t, err)
+	assert.Nil(t, dbml)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 3, parseErr.Line)
+}
+
+func TestParser_Parse_Span(t *testing.T) {
+	spec := `Project p {
+  note: 'n'
+}
+Table users as U {
+  id int [pk]
+  name varchar(10)
+  indexes {
+    (id, name) [unique]
+    name
+  }
+}
+TablePartial audit {
+  created_at timestamp
+}
+Enum status {
+  active [note: 'a']
+  blocked
+}
+Ref fk: posts.user_id > users.id [delete: cascade]
+Ref {
+  a.b - c.d
+}
+TableGroup g {
+  users
+}
+Note n {
+  'x'
+}`
+
+	dbml, err := p(spec).Parse(context.Background())
+	require.NoError(t, err)
+
+	text := func(span core.Span) string {
+		return spec[span.Start.Offset:span.End.Offset]
+	}
+
+	assert.Equal(t, "Project p {\n  note: 'n'\n}", text(dbml.Project.Span))
+
+	users := dbml.Tables[0]
+	assert.Equal(t, core.Span{Start: pos(26, 4, 1), End: pos(128, 11, 2)}, users.Span)
+	assert.Equal(t, "id int [pk]", text(users.Columns[0].Span))
+	assert.Equal(t, core.Span{Start: pos(61, 6, 3), End: pos(77, 6, 19)}, users.Columns[1].Span)
+	assert.Equal(t, "name varchar(10)", text(users.Columns[1].Span))
+	assert.Equal(t, "(id, name) [unique]", text(users.Indexes[0].Span))
+	assert.Equal(t, "name", text(users.Indexes[1].Span))
+
+	assert.Equal(t, "TablePartial audit {\n  created_at timestamp\n}", text(dbml.TablePartials[0].Span))
+	assert.Equal(t, "created_at timestamp", text(dbml.TablePartials[0].Columns[0].Span))
+
+	assert.Equal(t, "Enum status {\n  active [note: 'a']\n  blocked\n}", text(dbml.Enums[0].Span))
+	assert.Equal(t, "active [note: 'a']", text(dbml.Enums[0].Values[0].Span))
+	assert.Equal(t, "blocked", text(dbml.Enums[0].Values[1].Span))
+
+	assert.Equal(t, "Ref fk: posts.user_id > users.id [delete: cascade]", text(dbml.Refs[0].Span))
+	assert.Equal(t, "posts.user_id > users.id [delete: cascade]", text(dbml.Refs[0].Relationships[0].Span))
+	assert.Equal(t, "Ref {\n  a.b - c.d\n}", text(dbml.Refs[1].Span))
+	assert.Equal(t, "a.b - c.d", text(dbml.Refs[1].Relationships[0].Span))
+
+	assert.Equal(t, "TableGroup g {\n  users\n}", text(dbml.TableGroups[0].Span))
+	assert.Equal(t, "Note n {\n  'x'\n}", text(dbml.Notes[0].Span))
+}
+
+func TestParser_Parse_Comments(t *testing.T) {
+	spec := `// file header
+
+// users of shop
+Table users { // users table
+  id int [pk] // identifier
+
+  // user email
+  email varchar
+  // end of users
+}
+
+Ref: posts.user_id > users.id // fk
+
+// end of file`
+
+	dbml, err := NewParserWithMode(scanner.NewScanner(strings.NewReader(spec)), ParseComments, NoopLogger).
+		Parse(context.Background())
+	require.NoError(t, err)
+
+	texts := func(comments []core.Comment) []string {
+		var result []string
+		for _, comment := range comments {
+			result = append(result, comment.Text)
+		}
+		return result
+	}
+
+	users := dbml.Tables[0]
+	assert.Equal(t, []string{"// users of shop"}, texts(users.Trivia.Leading))
+	assert.Equal(t, []string{"// end of users"}, texts(users.Trivia.Inner))
+	assert.Empty(t, users.Trivia.Trailing)
+
+	assert.Equal(t, []string{"// users table"}, texts(users.Trivia.Header))
+	assert.Empty(t, users.Columns[0].Trivia.Leading)
+	assert.Equal(t, []string{"// identifier"}, texts(users.Columns[0].Trivia.Trailing))
+	assert.False(t, users.Columns[0].Trivia.EmptyLineBefore)
+
+	assert.Equal(t, []string{"// user email"}, texts(users.Columns[1].Trivia.Leading))
+	assert.True(t, users.Columns[1].Trivia.Leading[0].EmptyLineBefore)
+	assert.False(t, users.Columns[1].Trivia.EmptyLineBefore)
+	assert.Equal(t, core.Span{Start: pos(93, 7, 3), End: pos(106, 7, 16)}, users.Columns[1].Trivia.Leading[0].Span)
+
+	assert.Equal(t, []string{"// fk"}, texts(dbml.Refs[0].Trivia.Trailing))
+	assert.True(t, dbml.Refs[0].Trivia.EmptyLineBefore)
+	assert.Empty(t, dbml.Refs[0].Relationships[0].Trivia.Trailing)
+
+	assert.Equal(t, []string{"// file header"}, texts(dbml.Trivia.Leading))
+	assert.Equal(t, []string{"// end of file"}, texts(dbml.Trivia.Inner))
+	assert.True(t, dbml.Trivia.Inner[0].EmptyLineBefore)
+}
+
+func TestParser_Parse_Comments_BodyElements(t *testing.T) {
+	spec := `Project shop {
+  // engine
+  database_type: 'PostgreSQL'
+  version: '1.2' // semver
+  // shop note
+  Note: 'shop'
+}
+
+Table users {
+  id int
+  // audit columns
+  ~audit // partial
+
+  checks { // constraints
+    // positive id
+    ` + "`id > 0`" + ` // check
+  }
+
+  // users note
+  Note: 'users'
+}
+
+TableGroup g {
+  // main table
+  users // member
+  // group note
+  Note: 'group'
+}
+
+Note docs {
+  // content
+  'docs'
+}`
+
+	dbml, err := NewParserWithMode(scanner.NewScanner(strings.NewReader(spec)), ParseComments, NoopLogger).
+		Parse(context.Background())
+	require.NoError(t, err)
+
+	texts := func(comments []core.Comment) []string {
+		var result []string
+		for _, comment := range comments {
+			result = append(result, comment.Text)
+		}
+		return result
+	}
+
+	project := dbml.Project
+	assert.Equal(t, []string{"// engine"}, texts(project.DatabaseTypeElement.Trivia.Leading))
+	assert.Equal(t, []string{"// semver"}, texts(project.SettingElements[0].Trivia.Trailing))
+	assert.Equal(t, []string{"// shop note"}, texts(project.NoteElement.Trivia.Leading))
+	assert.Empty(t, project.Trivia.Inner)
+
+	users := dbml.Tables[0]
+	assert.Equal(t, []string{"// audit columns"}, texts(users.Partials[0].Trivia.Leading))
+	assert.Equal(t, []string{"// partial"}, texts(users.Partials[0].Trivia.Trailing))
+	assert.Equal(t, []string{"// constraints"}, texts(users.ChecksElement.Trivia.Header))
+	assert.True(t, users.ChecksElement.Trivia.EmptyLineBefore)
+	assert.Equal(t, []string{"// positive id"}, texts(users.Checks[0].Trivia.Leading))
+	assert.Equal(t, []string{"// check"}, texts(users.Checks[0].Trivia.Trailing))
+	assert.Equal(t, []string{"// users note"}, texts(users.NoteElement.Trivia.Leading))
+	assert.Empty(t, users.Trivia.Inner)
+
+	group := dbml.TableGroups[0]
+	assert.Equal(t, []string{"// main table"}, texts(group.Members[0].Trivia.Leading))
+	assert.Equal(t, []string{"// member"}, texts(group.Members[0].Trivia.Trailing))
+	assert.Equal(t, []string{"// group note"}, texts(group.NoteElement.Trivia.Leading))
+	assert.Empty(t, group.Trivia.Inner)
+
+	assert.Equal(t, []string{"// content"}, texts(dbml.Notes[0].ContentElement.Trivia.Leading))
+	assert.Empty(t, dbml.Notes[0].Trivia.Inner)
+}
+
+func TestParser_Parse_WithoutComments(t *testing.T) {
+	dbml, err := p(`// users
+Table users {
+  id int // identifier
+}`).Parse(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, core.Trivia{}, dbml.Tables[0].Trivia)
+	assert.Equal(t, core.Trivia{}, dbml.Tables[0].Columns[0].Trivia)
+}
+
+func TestParser_Parse_BlockComment(t *testing.T) {
+	dbml, err := p(`/*
+ * users of shop
+ */
+Table users {
+  id int /* identifier */ [pk]
+}`).Parse(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, dbml.Tables, 1)
+	assert.True(t, dbml.Tables[0].Columns[0].Settings.PK)
+	assert.Equal(t, 5, dbml.Tables[0].Columns[0].Span.Start.Line)
+}
+
+func TestParser_Parse_CRLF(t *testing.T) {
+	dbml, err := p("\uFEFFTable пользователи {\r\n  id int [pk]\r\n  имя varchar\r\n}\r\n").Parse(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, dbml.Tables, 1)
+	assert.Equal(t, "пользователи", dbml.Tables[0].Name)
+	assert.Equal(t, "имя", dbml.Tables[0].Columns[1].Name)
+	assert.Equal(t, core.Span{Start: pos(54, 3, 3), End: pos(68, 3, 14)}, dbml.Tables[0].Columns[1].Span)
+}
@@ -0,0 +1,662 @@
+package printer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/token"
+)
+
+const indent = "  "
+
+var identRegexp = regexp.MustCompile(`^\pL[\pL\pM\p{Nd}_]*$`)
+
+// typeRegexp matches column type in canonical form which can be printed as is, e.g. public.decimal(10,2)[].
+var typeRegexp = regexp.MustCompile(`^\pL[\pL\pM\p{Nd}_]*(\.\pL[\pL\pM\p{Nd}_]*)?(\([^()]*\))?(\[\])*$`)
+
+var relationshipSymbols = map[core.RelationshipType]string{
+	core.ManyToOne:  ">",
+	core.OneToMany:  "<",
+	core.OneToOne:   "-",
+	core.ManyToMany: "<>",
+}
+
+type printer struct {
+	w *bufio.Writer
+	// first is true until the first definition is printed
+	first bool
+}
+
+// Print writes DBML in canonical formatting: definitions are separated by empty line,
+// bodies are indented by two spaces, settings are written in fixed order.
+// Definitions are printed in order: project, enums, table partials, tables, refs, table groups, notes.
+// Comments and empty lines inside bodies are kept when DBML is parsed in parser.ParseComments mode,
+// file header comments are printed before all definitions.
+func Print(w io.Writer, dbml *core.DBML) error {
+	p := &printer{w: bufio.NewWriter(w), first: true}
+
+	if len(dbml.Trivia.Leading) > 0 {
+		p.comments(0, dbml.Trivia.Leading, true)
+		p.first = false
+	}
+	if hasProject(dbml.Project) {
+		p.project(dbml.Project)
+	}
+	for _, enum := range dbml.Enums {
+		p.enum(enum)
+	}
+	for _, partial := range dbml.TablePartials {
+		p.tablePartial(partial)
+	}
+	for _, table := range dbml.Tables {
+		p.table(table)
+	}
+	for _, ref := range dbml.Refs {
+		p.ref(ref)
+	}
+	for _, group := range dbml.TableGroups {
+		p.tableGroup(group)
+	}
+	for _, note := range dbml.Notes {
+		p.stickyNote(note)
+	}
+	if len(dbml.Trivia.Inner) > 0 {
+		if !p.first {
+			p.print("\n")
+		}
+		p.comments(0, dbml.Trivia.Inner, true)
+	}
+
+	return p.w.Flush()
+}
+
+// Sprint returns DBML in canonical formatting.
+func Sprint(dbml *core.DBML) string {
+	var b strings.Builder
+	_ = Print(&b, dbml)
+	return b.String()
+}
+
+// definition starts new top level definition.
+func (p *printer) definition(trivia core.Trivia, format string, args ...any) {
+	if !p.first {
+		p.print("\n")
+	}
+	p.first = false
+	p.comments(0, trivia.Leading, true)
+	if trivia.EmptyLineBefore && len(trivia.Leading) > 0 {
+		p.print("\n")
+	}
+	p.line(0, "%s%s", fmt.Sprintf(format, args...), header(trivia))
+}
+
+// block prints nested block of definition body, e.g. indexes of table.
+func (p *printer) block(depth int, title string, trivia core.Trivia, body func()) {
+	p.comments(depth, trivia.Leading, true)
+	if trivia.EmptyLineBefore && len(trivia.Leading) > 0 {
+		p.print("\n")
+	}
+	p.line(depth, "%s {%s", title, header(trivia))
+	body()
+	p.comments(depth+1, trivia.Inner, false)
+	p.line(depth, "}%s", trailing(trivia))
+}
+
+// end closes body of top level definition.
+func (p *printer) end(trivia core.Trivia) {
+	p.comments(1, trivia.Inner, false)
+	p.line(0, "}%s", trailing(trivia))
+}
+
+// leading prints leading comments of nested definition and empty line before it,
+// empty lines are skipped for the first definition of block.
+func (p *printer) leading(depth int, trivia core.Trivia, first bool) {
+	p.comments(depth, trivia.Leading, first)
+	if trivia.EmptyLineBefore && !(first && len(trivia.Leading) == 0) {
+		p.print("\n")
+	}
+	// inner comments of single line definition are moved before it,
+	// empty line before the first of them is empty line before definition, which is already printed
+	p.comments(depth, trivia.Inner, true)
+}
+
+func (p *printer) comments(depth int, comments []core.Comment, first bool) {
+	for i, comment := range comments {
+		if comment.EmptyLineBefore && !(first && i == 0) {
+			p.print("\n")
+		}
+		p.line(depth, "%s", comment.Text)
+	}
+}
+
+func (p *printer) line(depth int, format string, args ...any) {
+	p.print(strings.Repeat(indent, depth))
+	p.print(fmt.Sprintf(format, args...))
+	p.print("\n")
+}
+
+func (p *printer) print(s string) {
+	_, _ = p.w.WriteString(s)
+}
+
+// hasProject reports whether project is declared: parsed project has span, built one has any field set.
+func hasProject(project core.Project) bool {
+	return project.Span != (core.Span{}) || project.Name != "" || project.Note != "" ||
+		project.DatabaseType != "" || len(project.Settings) > 0
+}
+
+func (p *printer) project(project core.Project) {
+	if project.Name == "" {
+		p.definition(project.Trivia, "Project {")
+	} else {
+		p.definition(project.Trivia, "Project %s {", name(project.Name))
+	}
+	first := true
+	if project.DatabaseType != "" {
+		trivia := project.DatabaseTypeElement.Trivia
+		p.leading(1, trivia, first)
+		p.line(1, "database_type: %s%s", str(1, project.DatabaseType), trailing(trivia))
+		first = false
+	}
+	for _, setting := range orderedSettings(project.SettingElements, project.Settings) {
+		p.leading(1, setting.Trivia, first)
+		p.line(1, "%s: %s%s", name(setting.Name), settingValue(1, setting), trailing(setting.Trivia))
+		first = false
+	}
+	if project.Note != "" {
+		trivia := project.NoteElement.Trivia
+		p.leading(1, trivia, first)
+		p.line(1, "Note: %s%s", str(1, project.Note), trailing(trivia))
+	}
+	p.end(project.Trivia)
+}
+
+func (p *printer) enum(enum core.Enum) {
+	p.definition(enum.Trivia, "Enum %s {", qualifiedName(enum.Schema, enum.Name))
+	for i, value := range enum.Values {
+		p.leading(1, value.Trivia, i == 0)
+		if value.Note != "" {
+			p.line(1, "%s [note: %s]%s", name(value.Name), str(1, value.Note), trailing(value.Trivia))
+		} else {
+			p.line(1, "%s%s", name(value.Name), trailing(value.Trivia))
+		}
+	}
+	p.end(enum.Trivia)
+}
+
+func (p *printer) tablePartial(partial core.TablePartial) {
+	p.definition(partial.Trivia, "TablePartial %s%s {", name(partial.Name), tableSettings(partial.Settings))
+	p.tableBody(core.Table{
+		Note:           partial.Note,
+		Columns:        partial.Columns,
+		Indexes:        partial.Indexes,
+		Checks:         partial.Checks,
+		NoteElement:    partial.NoteElement,
+		IndexesElement: partial.IndexesElement,
+		ChecksElement:  partial.ChecksElement,
+	})
+	p.end(partial.Trivia)
+}
+
+func (p *printer) table(table core.Table) {
+	header := "Table " + qualifiedName(table.Schema, table.Name)
+	if table.As != "" {
+		header += " as " + alias(table.As)
+	}
+	p.definition(table.Trivia, "%s%s {", header, tableSettings(table.Settings))
+	p.tableBody(table)
+	p.end(table.Trivia)
+}
+
+func (p *printer) tableBody(table core.Table) {
+	empty := len(table.Columns) == 0 && len(table.Partials) == 0
+
+	partials := table.Partials
+	for i, column := range table.Columns {
+		for len(partials) > 0 && partials[0].Position <= i {
+			p.injection(partials[0], i == 0 && len(partials) == len(table.Partials))
+			partials = partials[1:]
+		}
+		p.leading(1, column.Trivia, i == 0 && len(partials) == len(table.Partials))
+		p.column(column)
+	}
+	for i, partial := range partials {
+		p.injection(partial, len(table.Columns) == 0 && i == 0)
+	}
+
+	if len(table.Checks) > 0 {
+		p.section(&empty)
+		p.block(1, "checks", table.ChecksElement.Trivia, func() {
+			for i, check := range table.Checks {
+				p.leading(2, check.Trivia, i == 0)
+				if check.Name != "" {
+					p.line(2, "%s [name: %s]%s", expr(check.Expression), str(2, check.Name), trailing(check.Trivia))
+				} else {
+					p.line(2, "%s%s", expr(check.Expression), trailing(check.Trivia))
+				}
+			}
+		})
+	}
+
+	if len(table.Indexes) > 0 {
+		p.section(&empty)
+		p.block(1, "indexes", table.IndexesElement.Trivia, func() {
+			for i, index := range table.Indexes {
+				p.leading(2, index.Trivia, i == 0)
+				p.index(index)
+			}
+		})
+	}
+
+	if table.Note != "" {
+		p.section(&empty)
+		p.leading(1, table.NoteElement.Trivia, true)
+		p.line(1, "Note: %s%s", str(1, table.Note), trailing(table.NoteElement.Trivia))
+	}
+}
+
+func (p *printer) injection(injection core.TablePartialInjection, first bool) {
+	p.leading(1, injection.Trivia, first)
+	p.line(1, "~%s%s", name(injection.Name), trailing(injection.Trivia))
+}
+
+// section separates block of table body with empty line, when it is not the first one.
+func (p *printer) section(empty *bool) {
+	if !*empty {
+		p.print("\n")
+	}
+	*empty = false
+}
+
+func (p *printer) column(column core.Column) {
+	settings := columnSettings(column.Settings)
+	definition := name(column.Name) + " " + columnType(column)
+	if len(settings) > 0 {
+		definition += " [" + strings.Join(settings, ", ") + "]"
+	}
+	p.line(1, "%s%s", definition, trailing(column.Trivia))
+}
+
+func (p *printer) index(index core.Index) {
+	fields := make([]string, 0, len(index.Fields))
+	for _, field := range index.Fields {
+		if field.Type == core.IndexFieldTypeExpression {
+			fields = append(fields, expr(field.Value))
+		} else {
+			fields = append(fields, name(field.Value))
+		}
+	}
+
+	definition := strings.Join(fields, ", ")
+	if len(fields) != 1 {
+		definition = "(" + definition + ")"
+	}
+
+	var settings []string
+	if index.Settings.PK {
+		settings = append(settings, "pk")
+	}
+	if index.Settings.Unique {
+		settings = append(settings, "unique")
+	}
+	if index.Settings.Type != "" {
+		settings = append(settings, "type: "+index.Settings.Type)
+	}
+	if index.Settings.Name != "" {
+		settings = append(settings, "name: "+str(2, index.Settings.Name))
+	}
+	if index.Settings.Note != "" {
+		settings = append(settings, "note: "+str(2, index.Settings.Note))
+	}
+
+	if len(settings) > 0 {
+		definition += " [" + strings.Join(settings, ", ") + "]"
+	}
+	p.line(2, "%s%s", definition, trailing(index.Trivia))
+}
+
+func (p *printer) ref(ref core.Ref) {
+	if len(ref.Relationships) == 1 && len(ref.Trivia.Header) == 0 && len(ref.Trivia.Inner) == 0 &&
+		!hasTrivia(ref.Relationships[0].Trivia) {
+		if ref.Name != "" {
+			p.definition(ref.Trivia, "Ref %s: %s%s", ref.Name, relationship(ref.Relationships[0]), trailing(ref.Trivia))
+		} else {
+			p.definition(ref.Trivia, "Ref: %s%s", relationship(ref.Relationships[0]), trailing(ref.Trivia))
+		}
+		return
+	}
+
+	if ref.Name != "" {
+		p.definition(ref.Trivia, "Ref %s {", ref.Name)
+	} else {
+		p.definition(ref.Trivia, "Ref {")
+	}
+	for i, rel := range ref.Relationships {
+		p.leading(1, rel.Trivia, i == 0)
+		p.line(1, "%s%s", relationship(rel), trailing(rel.Trivia))
+	}
+	p.end(ref.Trivia)
+}
+
+func (p *printer) tableGroup(group core.TableGroup) {
+	header := "TableGroup " + name(group.Name)
+	if group.Color != "" {
+		header += " [color: " + group.Color + "]"
+	}
+	p.definition(group.Trivia, "%s {", header)
+	for i, member := range group.Members {
+		p.leading(1, member.Trivia, i == 0)
+		p.line(1, "%s%s", qualifiedName(member.Schema, member.Name), trailing(member.Trivia))
+	}
+	if group.Note != "" {
+		if len(group.Members) > 0 {
+			p.print("\n")
+		}
+		p.leading(1, group.NoteElement.Trivia, true)
+		p.line(1, "Note: %s%s", str(1, group.Note), trailing(group.NoteElement.Trivia))
+	}
+	p.end(group.Trivia)
+}
+
+func (p *printer) stickyNote(note core.StickyNote) {
+	p.definition(note.Trivia, "Note %s {", name(note.Name))
+	p.leading(1, note.ContentElement.Trivia, true)
+	p.line(1, "%s%s", str(1, note.Content), trailing(note.ContentElement.Trivia))
+	p.end(note.Trivia)
+}
+
+// trailing returns trailing comments of definition prefixed with space.
+func trailing(trivia core.Trivia) string {
+	return inline(trivia.Trailing)
+}
+
+// header returns comments after opening brace of definition prefixed with space.
+func header(trivia core.Trivia) string {
+	return inline(trivia.Header)
+}
+
+func inline(comments []core.Comment) string {
+	var s string
+	for _, comment := range comments {
+		s += " " + comment.Text
+	}
+	return s
+}
+
+func hasTrivia(trivia core.Trivia) bool {
+	return len(trivia.Leading) > 0 || len(trivia.Header) > 0 || len(trivia.Trailing) > 0 || len(trivia.Inner) > 0
+}
+
+func tableSettings(settings core.TableSettings) string {
+	var s []string
+	if settings.HeaderColor != "" {
+		s = append(s, "headercolor: "+settings.HeaderColor)
+	}
+	if settings.Note != "" {
+		s = append(s, "note: "+str(0, settings.Note))
+	}
+	s = append(s, extraSettings(orderedSettings(settings.ExtraElements, settings.Extra))...)
+
+	if len(s) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(s, ", ") + "]"
+}
+
+// extraSettings returns unknown settings in order of declaration.
+func extraSettings(extra []setting) []string {
+	s := make([]string, 0, len(extra))
+	for _, setting := range extra {
+		if setting.Value == "" && setting.Raw == "" {
+			s = append(s, name(setting.Name))
+		} else {
+			s = append(s, name(setting.Name)+": "+settingValue(0, setting))
+		}
+	}
+	return s
+}
+
+// setting is unknown setting with value taken from settings map.
+type setting struct {
+	core.SettingElement
+	Value string
+}
+
+// orderedSettings returns unknown settings of map in order of declaration,
+// settings without declaration, e.g. of built DBML, follow them sorted by name.
+func orderedSettings(elements []core.SettingElement, values map[string]string) []setting {
+	list := make([]setting, 0, len(values))
+	declared := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		key := strings.ToLower(element.Name)
+		value, ok := values[key]
+		if !ok || declared[key] {
+			continue
+		}
+		declared[key] = true
+		list = append(list, setting{SettingElement: element, Value: value})
+	}
+
+	names := make([]string, 0, len(values)-len(list))
+	for key := range values {
+		if !declared[key] {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		list = append(list, setting{SettingElement: core.SettingElement{Name: key}, Value: values[key]})
+	}
+	return list
+}
+
+// settingValue returns value of unknown setting, literal keeps its source text while value is not changed,
+// other values are written as string.
+func settingValue(depth int, setting setting) string {
+	if setting.Raw != "" && (setting.Raw == setting.Value || setting.Raw == expr(setting.Value)) {
+		return setting.Raw
+	}
+	return str(depth, setting.Value)
+}
+
+// columnType returns structured type of column, Column.Type is used for built column without DataType.
+func columnType(column core.Column) string {
+	t := column.DataType
+	if t.Name == "" {
+		if typeRegexp.MatchString(column.Type) {
+			return column.Type
+		}
+		return name(column.Type)
+	}
+	s := qualifiedName(t.Schema, t.Name)
+	if len(t.Args) > 0 {
+		s += "(" + strings.Join(t.Args, ", ") + ")"
+	}
+	return s + strings.Repeat("[]", t.ArrayDimensions)
+}
+
+func columnSettings(settings core.ColumnSetting) []string {
+	var s []string
+	if settings.PK {
+		s = append(s, "pk")
+	}
+	if settings.Increment {
+		s = append(s, "increment")
+	}
+	if settings.Unique {
+		s = append(s, "unique")
+	}
+	switch settings.Null {
+	case core.NullabilityNullable:
+		s = append(s, "null")
+	case core.NullabilityNotNull:
+		s = append(s, "not null")
+	}
+	if def := settings.Default; def.Type != core.ColumnDefaultTypeUnknown || def.Raw != "" || def.Value != nil {
+		s = append(s, "default: "+columnDefault(settings.Default))
+	}
+	if settings.Ref.Type != core.None {
+		ref := fmt.Sprintf("ref: %s %s", relationshipSymbols[settings.Ref.Type], endpoint(settings.Ref.To))
+		s = append(s, ref+referentialActions(settings.Ref.OnDelete, settings.Ref.OnUpdate))
+	}
+	for _, check := range settings.Checks {
+		s = append(s, "check: "+expr(check.Expression))
+	}
+	if settings.Note != "" {
+		s = append(s, "note: "+str(1, settings.Note))
+	}
+	return s
+}
+
+// columnDefault returns default of column, text of built default without Raw is made of its Value.
+func columnDefault(def core.ColumnDefault) string {
+	text := def.Raw
+	if text == "" {
+		switch value := def.Value.(type) {
+		case nil:
+		case string:
+			text = value
+		case float64:
+			text = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			text = fmt.Sprint(value)
+		}
+	}
+
+	switch def.Type {
+	case core.ColumnDefaultTypeString:
+		return quote(text, '\'')
+	case core.ColumnDefaultTypeExpression:
+		return expr(text)
+	case core.ColumnDefaultTypeNull:
+		return "null"
+	default:
+		return text
+	}
+}
+
+func relationship(rel core.Relationship) string {
+	return fmt.Sprintf(
+		"%s %s %s%s",
+		endpoint(rel.From),
+		relationshipSymbols[rel.Type],
+		endpoint(rel.To),
+		referentialActions(rel.OnDelete, rel.OnUpdate),
+	)
+}
+
+func endpoint(e core.RelationshipEndpoint) string {
+	columns := make([]string, 0, len(e.Columns))
+	for _, column := range e.Columns {
+		columns = append(columns, name(column))
+	}
+
+	table := qualifiedName(e.Schema, e.Table)
+	if len(columns) == 1 {
+		return table + "." + columns[0]
+	}
+	return fmt.Sprintf("%s.(%s)", table, strings.Join(columns, ", "))
+}
+
+func referentialActions(onDelete, onUpdate core.ReferentialAction) string {
+	var actions []string
+	if onDelete != core.ReferentialActionNone {
+		actions = append(actions, "delete: "+onDelete.String())
+	}
+	if onUpdate != core.ReferentialActionNone {
+		actions = append(actions, "update: "+onUpdate.String())
+	}
+	if len(actions) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(actions, ", ") + "]"
+}
+
+// name returns name as identifier or as double quoted string when it is not plain identifier or it is keyword.
+func name(s string) string {
+	if identRegexp.MatchString(s) && token.Lookup(s) == token.IDENT {
+		return s
+	}
+	return quote(s, '"')
+}
+
+// qualifiedName returns name with schema prefix, each part is written with name.
+func qualifiedName(schema, n string) string {
+	if schema != "" {
+		schema = name(schema)
+	}
+	return core.QualifiedName(schema, name(n))
+}
+
+// alias returns table alias as identifier or as single quoted string.
+func alias(s string) string {
+	if identRegexp.MatchString(s) && token.Lookup(s) == token.IDENT {
+		return s
+	}
+	return quote(s, '\'')
+}
+
+// str returns string in single quotes, multi-line string is written as triple quoted block indented by depth.
+// Multi-line string without unindented lines is written in single quotes,
+// because indentation of block is removed by parser.
+func str(depth int, s string) string {
+	if !strings.Contains(s, "\n") || strings.Contains(s, "\r") || !hasUnindentedLine(s) {
+		return quote(s, '\'')
+	}
+
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if strings.Contains(s, "'''") {
+		s = strings.ReplaceAll(s, "'", `\'`)
+	}
+
+	var b strings.Builder
+	b.WriteString("'''\n")
+	for _, line := range strings.Split(s, "\n") {
+		if line != "" {
+			b.WriteString(strings.Repeat(indent, depth+1))
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat(indent, depth))
+	b.WriteString("'''")
+	return b.String()
+}
+
+// quote returns single line string in quotes with escaped quote, backslash and line breaks.
+func quote(s string, q byte) string {
+	var b strings.Builder
+	b.WriteByte(q)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\', q:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	b.WriteByte(q)
+	return b.String()
+}
+
+func hasUnindentedLine(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		if line != "" && line[0] != ' ' && line[0] != '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+func expr(s string) string {
+	return "`" + s + "`"
+}
@@ -0,0 +1,464 @@
+package printer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/parser"
+)
+
+func parse(t *testing.T, spec string) *core.DBML {
+	t.Helper()
+
+	dbml, err := parser.Parse(context.Background(), strings.NewReader(spec))
+	require.NoError(t, err)
+
+	return dbml
+}
+
+// stripSource zeroes all core.Span values and raw source texts reachable from v.
+func stripSource(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			stripSource(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(core.Span{}) {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			switch field := v.Type().Field(i); {
+			case field.Name == "NoteRaw", field.Name == "ContentRaw", field.Name == "Source",
+				field.Name == "Raw" && v.Type() == reflect.TypeOf(core.ColumnType{}),
+				field.Name == "Raw" && v.Type() == reflect.TypeOf(core.SettingElement{}) && isQuoted(v.Field(i).String()):
+				// printer writes strings and types in canonical form
+				v.Field(i).SetString("")
+			default:
+				stripSource(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			stripSource(v.Index(i))
+		}
+	}
+}
+
+// isQuoted reports whether raw value is string literal.
+func isQuoted(raw string) bool {
+	return strings.HasPrefix(raw, "'") || strings.HasPrefix(raw, `"`)
+}
+
+const spec = `
+Project shop {
+	database_type: 'PostgreSQL'
+	version: '1.2'
+	sql_dialect_version: 16
+	"type": '''
+	  multi
+	  line
+	'''
+	note: 'shop\'s "database" in C:\\db'
+}
+
+enum billing.status {
+	paid [note: 'is paid']
+	"not paid"
+}
+
+TablePartial audit [headercolor: #fff] {
+	created_at timestamp [not null, default: ` + "`now()`" + `]
+	Note: 'audit columns'
+}
+
+Table users as U [headercolor: #3498DB, note: 'users', owner: "billing", "type": 1, archived] {
+	id int [pk, increment]
+	"type" varchar(255) [null, default: 'user', note: '''multi
+line''']
+	~audit
+	score "double precision" [default: -1.5e3]
+	tags text[][] [default: 'it\'s', note: '  indented\n  lines']
+	"say \"hi\"" int
+	amount decimal(10,2) [check: ` + "`amount > 0`" + `, unique]
+
+	indexes {
+		(id, ` + "`lower(type)`" + `) [unique, type: btree, name: 'users_idx']
+		id [pk]
+		` + "`score * 2`" + `
+	}
+
+	checks {
+		` + "`score > 0`" + ` [name: 'positive_score']
+		` + "`amount < 100`" + `
+	}
+
+	Note: 'users table'
+}
+
+Table billing.invoices {
+	id int
+	user_id int [ref: > U.id [delete: cascade, update: set null]]
+	country varchar
+	status billing.status
+	active boolean [default: false]
+	~audit
+}
+
+Ref: billing.invoices.(id, country) <> users.(id, type) [update: no action]
+Ref named {
+	billing.invoices.user_id - users.id
+	users.id < billing.invoices.user_id
+}
+
+TableGroup g [color: #aaa, note: 'billing tables'] {
+	users
+	billing."invoices"
+}
+
+TableGroup empty {
+	Note: 'empty group'
+}
+
+Note docs {
+	'''
+	# Title
+
+	  * it's \\ escaped \'''
+	* ends with new line
+
+	'''
+}
+`
+
+func TestPrint_RoundTrip(t *testing.T) {
+	expected := parse(t, spec)
+	printed := Sprint(expected)
+	actual := parse(t, printed)
+
+	stripSource(reflect.ValueOf(expected))
+	stripSource(reflect.ValueOf(actual))
+	assert.Equal(t, expected, actual, printed)
+
+	assert.Equal(t, printed, Sprint(actual))
+}
+
+func TestPrint(t *testing.T) {
+	printed := Sprint(parse(t, `
+Ref: posts.user_id > users.id
+table users [retention: 30, headercolor: #aaa, note: 'table', archived] { id int [pk]
+Note: 'users' }
+Table posts {
+	user_id int  [ not null ,ref: > users.id ]
+indexes { user_id }
+}
+Enum status { active }
+`))
+
+	assert.Equal(t, `Enum status {
+  active
+}
+
+Table users [headercolor: #aaa, note: 'table', retention: 30, archived] {
+  id int [pk]
+
+  Note: 'users'
+}
+
+Table posts {
+  user_id int [not null, ref: > users.id]
+
+  indexes {
+    user_id
+  }
+}
+
+Ref: posts.user_id > users.id
+`, printed)
+
+	built := core.Table{
+		Name: "users",
+		Columns: []core.Column{
+			{Name: "id", Type: "int", Settings: core.ColumnSetting{
+				Default: core.ColumnDefault{Value: 1, Type: core.ColumnDefaultTypeNumber},
+			}},
+			{Name: "price", Type: "decimal(10,2)[]"},
+			{Name: "created_at", Type: "timestamp with time zone", Settings: core.ColumnSetting{
+				Default: core.ColumnDefault{Value: "now()", Type: core.ColumnDefaultTypeExpression},
+			}},
+			{Name: "rate", Type: "float", Settings: core.ColumnSetting{
+				Default: core.ColumnDefault{Value: 0.5, Type: core.ColumnDefaultTypeNumber},
+			}},
+			{Name: "name", Type: "varchar", Settings: core.ColumnSetting{
+				Default: core.ColumnDefault{Value: "it's", Type: core.ColumnDefaultTypeString},
+			}},
+		},
+		Settings: core.TableSettings{Extra: map[string]string{"retention": "30", "archived": ""}},
+	}
+	assert.Equal(t, `Table users [archived, retention: '30'] {
+  id int [default: 1]
+  price decimal(10,2)[]
+  created_at "timestamp with time zone" [default: `+"`now()`"+`]
+  rate float [default: 0.5]
+  name varchar [default: 'it\'s']
+}
+`, Sprint(&core.DBML{Tables: []core.Table{built}}))
+}
+
+func TestPrint_TableSettings_Edited(t *testing.T) {
+	dbml := parse(t, `Table users [owner: 'a', retention: 30, archived, stage: dev] { id int }`)
+	dbml.Tables[0].Settings.Extra["owner"] = "b"
+	dbml.Tables[0].Settings.Extra["kind"] = ""
+	delete(dbml.Tables[0].Settings.Extra, "stage")
+
+	assert.Equal(t, `Table users [owner: 'b', retention: 30, archived, kind] {
+  id int
+}
+`, Sprint(dbml))
+
+	assert.Equal(t, `Table users [Bar: 2] {
+  id int
+}
+`, Sprint(parse(t, `Table users [bar: 1, Bar: 2] { id int }`)))
+}
+
+func TestPrint_Strings(t *testing.T) {
+	printed := Sprint(parse(t, `
+Table users {
+  id int [note: '''
+      first
+    second
+  ''', default: 'it\'s']
+
+  Note: 'single line with \\ and \' and "'
+}
+`))
+
+	assert.Equal(t, `Table users {
+  id int [default: 'it\'s', note: '''
+      first
+    second
+  ''']
+
+  Note: 'single line with \\ and \' and "'
+}
+`, printed)
+}
+
+func TestPrint_Project(t *testing.T) {
+	spec := `Project {
+  database_type: 'PostgreSQL'
+  version: '1.2'
+  sql_dialect_version: 16
+  "owner team": billing
+}
+`
+	assert.Equal(t, spec, Sprint(parse(t, spec)))
+
+	assert.Equal(t, `Project {
+  Note: 'built project'
+}
+`, Sprint(&core.DBML{Project: core.Project{Note: "built project"}}))
+
+	assert.Equal(t, `Project {
+  owner: 'billing'
+  version: '1.2'
+}
+`, Sprint(&core.DBML{Project: core.Project{Settings: map[string]string{"version": "1.2", "owner": "billing"}}}))
+
+	edited := parse(t, "Project p {\n owner: 'a'\n version: 1\n stage: dev\n}")
+	edited.Project.Settings["owner"] = "b"
+	edited.Project.Settings["version"] = "2"
+	edited.Project.Settings["region"] = "eu"
+	delete(edited.Project.Settings, "stage")
+	assert.Equal(t, `Project p {
+  owner: 'b'
+  version: '2'
+  region: 'eu'
+}
+`, Sprint(edited))
+
+	assert.Equal(t, `Project {
+  Version: 2
+}
+`, Sprint(parse(t, "Project {\n  version: 1\n  Version: 2\n}")))
+
+	assert.Equal(t, "", Sprint(&core.DBML{}))
+}
+
+func TestPrint_Comments(t *testing.T) {
+	spec := `Enum status {
+  active // is active
+}
+
+// shop schema
+
+// users of shop
+Table users {
+  id int [pk] // identifier
+
+  // user email
+  email varchar
+
+  indexes {
+    // unique email
+    email [unique]
+  }
+  // end of users
+}
+
+Ref: posts.user_id > users.id // fk
+
+/*
+ * composite refs
+ */
+Ref {
+  // composite
+  a.b - c.d /* first */
+}
+
+// end of file
+`
+
+	dbml, err := parser.ParseWithMode(context.Background(), strings.NewReader(spec), parser.ParseComments)
+	require.NoError(t, err)
+
+	assert.Equal(t, spec, Sprint(dbml))
+}
+
+func TestPrint_Comments_FileHeader(t *testing.T) {
+	dbml, err := parser.ParseWithMode(context.Background(), strings.NewReader(`// file header
+
+// users of shop
+Table users {
+  id int
+}
+Enum e {
+  a
+}
+`), parser.ParseComments)
+	require.NoError(t, err)
+
+	printed := Sprint(dbml)
+	assert.Equal(t, `// file header
+
+Enum e {
+  a
+}
+
+// users of shop
+Table users {
+  id int
+}
+`, printed)
+
+	dbml, err = parser.ParseWithMode(context.Background(), strings.NewReader(printed), parser.ParseComments)
+	require.NoError(t, err)
+	assert.Equal(t, printed, Sprint(dbml))
+}
+
+func TestPrint_Comments_BodyElements(t *testing.T) {
+	spec := `Project shop { // shop project
+  // engine
+  database_type: 'PostgreSQL'
+  version: '1.2' // semver
+  sql_dialect_version: 16
+
+  // shop note
+  Note: 'shop'
+}
+
+TablePartial audit {
+  created_at timestamp
+
+  // partial note
+  Note: 'audited'
+}
+
+Table users { // users table
+  id int
+  // audit columns
+  ~audit // partial
+
+  checks { // constraints
+    // positive id
+    ` + "`id > 0`" + ` [name: 'chk_id'] // check
+    // end of checks
+  }
+
+  // lookups
+  indexes {
+    id // by id
+  }
+
+  // users note
+  Note: 'users' // note
+}
+
+TableGroup g { // group
+  // main table
+  users // member
+
+  // group note
+  Note: 'group'
+}
+
+Note docs {
+  // content
+  'docs' // text
+}
+`
+
+	dbml, err := parser.ParseWithMode(context.Background(), strings.NewReader(spec), parser.ParseComments)
+	require.NoError(t, err)
+
+	assert.Equal(t, spec, Sprint(dbml))
+}
+
+func TestPrint_Comments_Idempotent(t *testing.T) {
+	spec := `Table users {
+  id int
+
+  name varchar /* inline */ [null]
+  // leading
+
+  email varchar /* first */ [unique] /* second */
+}
+
+Enum status {
+
+  active /* inline */
+}
+`
+
+	printComments := func(spec string) string {
+		dbml, err := parser.ParseWithMode(context.Background(), strings.NewReader(spec), parser.ParseComments)
+		require.NoError(t, err)
+		return Sprint(dbml)
+	}
+
+	printed := printComments(spec)
+	assert.Equal(t, `Enum status {
+  active /* inline */
+}
+
+Table users {
+  id int
+
+  /* inline */
+  name varchar [null]
+  // leading
+
+  /* first */
+  email varchar [unique] /* second */
+}
+`, printed)
+	assert.Equal(t, printed, printComments(printed))
+}
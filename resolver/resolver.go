@@ -0,0 +1,183 @@
+package resolver
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/validate"
+)
+
+// Schema is DBML with linked definitions.
+type Schema struct {
+	// DBML with resolved table partials, linked definitions point to it.
+	DBML   *core.DBML
+	Tables []*Table
+	// Relationships of refs and inline refs in order of declaration,
+	// inline refs of table partial columns are declared by partial.
+	Relationships []*Relationship
+
+	lookup *core.Lookup
+	// linked tables by source table
+	tables map[*core.Table]*Table
+}
+
+// Table ...
+type Table struct {
+	Table   *core.Table
+	Columns []*Column
+	// Relationships where table is used as From or To endpoint in order of declaration.
+	Relationships []*Relationship
+
+	columns map[string]*Column
+}
+
+// Column ...
+type Column struct {
+	Table  *Table
+	Column *core.Column
+	// Enum is set when column type is declared enum.
+	Enum *core.Enum
+}
+
+// Relationship ...
+type Relationship struct {
+	// Relationship is source relationship, for inline ref it is built from column settings.
+	Relationship core.Relationship
+	From         Endpoint
+	To           Endpoint
+	// Inline is true when relationship is declared by column setting ref.
+	Inline bool
+}
+
+// Endpoint ...
+type Endpoint struct {
+	Table   *Table
+	Columns []*Column
+}
+
+// Resolve validates DBML and links its definitions.
+// Returns validate.ErrorList when DBML is invalid.
+func Resolve(dbml *core.DBML) (*Schema, error) {
+	if err := validate.Validate(dbml); err != nil {
+		return nil, err
+	}
+
+	resolved, err := dbml.ResolveTablePartials()
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		DBML:   resolved,
+		lookup: core.NewLookup(resolved),
+		tables: make(map[*core.Table]*Table, len(resolved.Tables)),
+	}
+
+	for i := range resolved.Tables {
+		schema.addTable(&resolved.Tables[i])
+	}
+
+	for _, table := range schema.Tables {
+		for _, column := range table.Columns {
+			ref := column.Column.Settings.Ref
+			if ref.Type == core.None {
+				continue
+			}
+
+			schema.addRelationship(core.Relationship{
+				From: core.RelationshipEndpoint{
+					Schema:  table.Table.Schema,
+					Table:   table.Table.Name,
+					Columns: []string{column.Column.Name},
+				},
+				To:       ref.To,
+				Type:     ref.Type,
+				OnDelete: ref.OnDelete,
+				OnUpdate: ref.OnUpdate,
+				Span:     column.Column.Span,
+			}, true)
+		}
+	}
+
+	for _, ref := range resolved.Refs {
+		for _, rel := range ref.Relationships {
+			schema.addRelationship(rel, false)
+		}
+	}
+
+	// inline refs and refs are collected separately, restore order of declaration
+	sort.SliceStable(schema.Relationships, func(i, j int) bool {
+		return schema.Relationships[i].Relationship.Span.Start.Offset < schema.Relationships[j].Relationship.Span.Start.Offset
+	})
+	for _, rel := range schema.Relationships {
+		rel.From.Table.Relationships = append(rel.From.Table.Relationships, rel)
+		if rel.To.Table != rel.From.Table {
+			rel.To.Table.Relationships = append(rel.To.Table.Relationships, rel)
+		}
+	}
+
+	return schema, nil
+}
+
+// Table finds table by name or by alias when schema is empty.
+// Names are compared case-insensitively, table without schema belongs to "public" schema.
+func (s *Schema) Table(schema, name string) *Table {
+	return s.tables[s.lookup.Table(schema, name)]
+}
+
+// Enum finds enum by name.
+func (s *Schema) Enum(schema, name string) *core.Enum {
+	return s.lookup.Enum(schema, name)
+}
+
+// Column finds column by name case-insensitively.
+func (t *Table) Column(name string) *Column {
+	return t.columns[strings.ToLower(name)]
+}
+
+func (s *Schema) addTable(table *core.Table) {
+	linked := &Table{
+		Table:   table,
+		Columns: make([]*Column, 0, len(table.Columns)),
+		columns: map[string]*Column{},
+	}
+
+	for i := range table.Columns {
+		column := &Column{
+			Table:  linked,
+			Column: &table.Columns[i],
+			Enum:   s.Enum(table.Columns[i].DataType.Schema, table.Columns[i].DataType.Name),
+		}
+		linked.Columns = append(linked.Columns, column)
+		linked.columns[strings.ToLower(column.Column.Name)] = column
+	}
+
+	s.Tables = append(s.Tables, linked)
+	s.tables[table] = linked
+}
+
+func (s *Schema) addRelationship(rel core.Relationship, inline bool) {
+	linked := &Relationship{
+		Relationship: rel,
+		From:         s.endpoint(rel.From),
+		To:           s.endpoint(rel.To),
+		Inline:       inline,
+	}
+
+	s.Relationships = append(s.Relationships, linked)
+}
+
+func (s *Schema) endpoint(endpoint core.RelationshipEndpoint) Endpoint {
+	table := s.Table(endpoint.Schema, endpoint.Table)
+
+	linked := Endpoint{
+		Table:   table,
+		Columns: make([]*Column, 0, len(endpoint.Columns)),
+	}
+	for _, column := range endpoint.Columns {
+		linked.Columns = append(linked.Columns, table.Column(column))
+	}
+
+	return linked
+}
@@ -0,0 +1,138 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/parser"
+	"github.com/artarts36/dbml-go/validate"
+)
+
+func resolve(t *testing.T, spec string) (*Schema, error) {
+	t.Helper()
+
+	dbml, err := parser.Parse(context.Background(), strings.NewReader(spec))
+	require.NoError(t, err)
+
+	return Resolve(dbml)
+}
+
+func TestResolve(t *testing.T) {
+	schema, err := resolve(t, `
+Enum billing.status {
+  paid
+}
+
+Enum role {
+  admin
+}
+
+TablePartial audit {
+  created_by int [ref: > U.id]
+}
+
+Table users as U {
+  id int [pk]
+  role role
+}
+
+Table billing.invoices {
+  id int
+  user_id int [ref: > users.id [delete: cascade]]
+  status billing.status
+  ~audit
+}
+
+Ref: billing.invoices.(id, user_id) - U.(id, id)
+`)
+	require.NoError(t, err)
+
+	users := schema.Table("", "U")
+	require.NotNil(t, users)
+	assert.Same(t, users, schema.Table("public", "Users"))
+	assert.Equal(t, "users", users.Table.Name)
+
+	invoices := schema.Table("billing", "invoices")
+	require.NotNil(t, invoices)
+	assert.Nil(t, schema.Table("", "invoices"))
+
+	assert.Same(t, schema.Enum("", "role"), users.Column("role").Enum)
+	assert.Same(t, schema.Enum("billing", "status"), invoices.Column("status").Enum)
+	assert.Nil(t, invoices.Column("id").Enum)
+	assert.Same(t, invoices, invoices.Column("Status").Table)
+
+	require.Len(t, schema.Relationships, 3)
+
+	createdBy := schema.Relationships[0]
+	assert.True(t, createdBy.Inline)
+	assert.Same(t, invoices.Column("created_by"), createdBy.From.Columns[0])
+	assert.Same(t, users, createdBy.To.Table)
+
+	userID := schema.Relationships[1]
+	assert.True(t, userID.Inline)
+	assert.Equal(t, core.RelationshipType(core.ManyToOne), userID.Relationship.Type)
+	assert.Equal(t, core.ReferentialActionCascade, userID.Relationship.OnDelete)
+	assert.Same(t, invoices, userID.From.Table)
+	assert.Equal(t, []*Column{invoices.Column("user_id")}, userID.From.Columns)
+	assert.Same(t, users, userID.To.Table)
+	assert.Equal(t, []*Column{users.Column("id")}, userID.To.Columns)
+
+	composite := schema.Relationships[2]
+	assert.False(t, composite.Inline)
+	assert.Equal(t, core.RelationshipType(core.OneToOne), composite.Relationship.Type)
+	assert.Equal(t, []*Column{invoices.Column("id"), invoices.Column("user_id")}, composite.From.Columns)
+	assert.Equal(t, []*Column{users.Column("id"), users.Column("id")}, composite.To.Columns)
+
+	assert.Equal(t, schema.Relationships, users.Relationships)
+	assert.Equal(t, schema.Relationships, invoices.Relationships)
+}
+
+func TestResolve_RelationshipsOrder(t *testing.T) {
+	schema, err := resolve(t, `
+Table users {
+  id int
+}
+
+Ref: posts.author_id > users.id
+
+Table posts {
+  id int
+  author_id int
+  editor_id int [ref: > users.id]
+}
+
+Ref: posts.id - users.id
+`)
+	require.NoError(t, err)
+
+	require.Len(t, schema.Relationships, 3)
+	assert.Equal(t, "author_id", schema.Relationships[0].Relationship.From.Columns[0])
+	assert.False(t, schema.Relationships[0].Inline)
+	assert.Equal(t, "editor_id", schema.Relationships[1].Relationship.From.Columns[0])
+	assert.True(t, schema.Relationships[1].Inline)
+	assert.Equal(t, "id", schema.Relationships[2].Relationship.From.Columns[0])
+	assert.False(t, schema.Relationships[2].Inline)
+
+	assert.Equal(t, schema.Relationships, schema.Table("", "users").Relationships)
+	assert.Equal(t, schema.Relationships, schema.Table("", "posts").Relationships)
+}
+
+func TestResolve_Invalid(t *testing.T) {
+	_, err := resolve(t, `
+Table users {
+  id int
+}
+
+Ref: posts.user_id > users.id
+`)
+
+	var errs validate.ErrorList
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, "[6:6] ref: table posts not found", errs.Error())
+}
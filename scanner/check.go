@@ -1,5 +1,21 @@
 package scanner
 
-func isWhitespace(ch rune) bool { return ch == ' ' || ch == '\t' || ch == '\n' }
-func isLetter(ch rune) bool     { return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') }
-func isDigit(ch rune) bool      { return (ch >= '0' && ch <= '9') }
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+func isWhitespace(ch rune) bool { return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' }
+func isLetter(ch rune) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= utf8.RuneSelf && unicode.IsLetter(ch))
+}
+func isDigit(ch rune) bool { return (ch >= '0' && ch <= '9') }
+
+// isIdentPart reports whether ch continues identifier, combining marks are allowed after the first rune.
+func isIdentPart(ch rune) bool {
+	return isLetter(ch) || isDigit(ch) || ch == '_' ||
+		(ch >= utf8.RuneSelf && (unicode.IsMark(ch) || unicode.IsDigit(ch)))
+}
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
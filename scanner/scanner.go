@@ -4,24 +4,46 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"strings"
 
 	"github.com/artarts36/dbml-go/token"
 )
 
 const eof = rune(0)
 
+// bom is UTF-8 byte order mark, it is skipped at the start of input.
+const bom = '\uFEFF'
+
 // Scanner represents a lexical scanner.
 type Scanner struct {
 	r  *bufio.Reader
 	ch rune // for peek
-	l  uint
-	c  uint
+	w  int  // byte width of ch
+
+	exhausted bool // reader returned an error
+
+	// position of ch
+	offset int
+	l      uint
+	c      uint
+
+	// start and end positions of the last read token
+	pos token.Position
+	end token.Position
+
+	// source text read so far, indexed by offset
+	src *bytes.Buffer
 }
 
 // NewScanner returns a new instance of Scanner.
 func NewScanner(r io.Reader) *Scanner {
-	s := &Scanner{r: bufio.NewReader(r), l: 1, c: 0}
+	src := &bytes.Buffer{}
+	s := &Scanner{r: bufio.NewReader(io.TeeReader(r, src)), l: 1, c: 0, src: src}
 	s.next()
+	if s.ch == bom {
+		s.next()
+		s.c = 1
+	}
 	return s
 }
 
@@ -31,7 +53,15 @@ func (s *Scanner) Read() (tok token.Token, lit string) {
 		s.next()
 	}
 
-	// Otherwise read the individual character.
+	s.pos = s.position()
+	tok, lit = s.scan()
+	s.end = s.position()
+
+	return tok, lit
+}
+
+func (s *Scanner) scan() (tok token.Token, lit string) {
+	// Read the individual character.
 	switch {
 	case isLetter(s.ch):
 		return s.scanIdent()
@@ -44,9 +74,15 @@ func (s *Scanner) Read() (tok token.Token, lit string) {
 		switch ch {
 		case eof:
 			return token.EOF, ""
+		case '+':
+			return token.ADD, lit
 		case '-':
 			return token.SUB, lit
 		case '<':
+			if s.ch == '>' {
+				s.next()
+				return token.LSSGTR, "<>"
+			}
 			return token.LSS, lit
 		case '>':
 			return token.GTR, lit
@@ -66,17 +102,24 @@ func (s *Scanner) Read() (tok token.Token, lit string) {
 			return token.SEMICOLON, lit
 		case ':':
 			return token.COLON, lit
+		case '~':
+			return token.TILDE, lit
 		case ',':
 			return token.COMMA, lit
 		case '.':
 			return token.PERIOD, lit
 		case '`':
 			return s.scanExpression()
+		case '#':
+			return s.scanColor()
 		case '\'', '"':
 			return s.scanString(ch)
 		case '/':
-			if s.ch == '/' {
+			switch s.ch {
+			case '/':
 				return token.COMMENT, s.scanComment()
+			case '*':
+				return s.scanBlockComment()
 			}
 			return token.ILLEGAL, string(ch)
 		}
@@ -87,13 +130,36 @@ func (s *Scanner) Read() (tok token.Token, lit string) {
 func (s *Scanner) scanComment() string {
 	var buf bytes.Buffer
 	buf.WriteString("/")
-	for s.ch != '\n' && s.ch != eof {
+	for s.ch != '\n' && s.ch != '\r' && s.ch != eof {
 		buf.WriteRune(s.ch)
 		s.next()
 	}
 	return buf.String()
 }
 
+// scanBlockComment scans /* comment */, current char must be '*'.
+func (s *Scanner) scanBlockComment() (token.Token, string) {
+	var buf bytes.Buffer
+	buf.WriteString("/")
+	for {
+		switch s.ch {
+		case eof:
+			return token.ILLEGAL, buf.String()
+		case '*':
+			buf.WriteRune(s.ch)
+			s.next()
+			if s.ch == '/' && buf.Len() > 2 {
+				buf.WriteRune(s.ch)
+				s.next()
+				return token.COMMENT, buf.String()
+			}
+		default:
+			buf.WriteRune(s.ch)
+			s.next()
+		}
+	}
+}
+
 func (s *Scanner) scanNumber() (token.Token, string) {
 	var buf bytes.Buffer
 	countDot := 0
@@ -104,10 +170,28 @@ func (s *Scanner) scanNumber() (token.Token, string) {
 		buf.WriteRune(s.ch)
 		s.next()
 	}
+	if countDot > 1 {
+		return token.ILLEGAL, buf.String()
+	}
+	if s.ch == 'e' || s.ch == 'E' {
+		// scientific notation: 1e10, 1.5E-3
+		buf.WriteRune(s.ch)
+		s.next()
+		if s.ch == '-' || s.ch == '+' {
+			buf.WriteRune(s.ch)
+			s.next()
+		}
+		if !isDigit(s.ch) {
+			return token.ILLEGAL, buf.String()
+		}
+		for isDigit(s.ch) {
+			buf.WriteRune(s.ch)
+			s.next()
+		}
+		return token.FLOAT, buf.String()
+	}
 	if countDot < 1 {
 		return token.INT, buf.String()
-	} else if countDot > 1 {
-		return token.ILLEGAL, buf.String()
 	}
 	return token.FLOAT, buf.String()
 }
@@ -115,60 +199,114 @@ func (s *Scanner) scanNumber() (token.Token, string) {
 func (s *Scanner) scanString(quo rune) (token.Token, string) {
 	switch quo {
 	case '"':
-		lit, ok := s.scanTo(quo)
+		lit, ok := s.scanTo(quo, true)
 		if ok {
 			return token.DSTRING, lit
 		}
 		return token.ILLEGAL, lit
 	case '\'':
 		if s.ch != '\'' {
-			lit, ok := s.scanTo(quo)
+			lit, ok := s.scanTo(quo, true)
 			if ok {
 				return token.STRING, lit
 			}
 			return token.ILLEGAL, lit
 		}
-		// Handle Triple quote string
-		var buf bytes.Buffer
 		s.next()
 		if s.ch == '\'' { // triple quote string
 			s.next()
-			count := 0
-			for count < 3 {
-				switch s.ch {
-				case '\'':
-					count++
-				case eof:
-					return token.ILLEGAL, buf.String()
-				}
-				buf.WriteRune(s.ch)
-				s.next()
-			}
-			return token.TSTRING, buf.String()[:buf.Len()-count]
+			return s.scanTripleString()
 		}
-		return token.ILLEGAL, buf.String()
+		// empty string
+		return token.STRING, ""
 	default:
 		return token.ILLEGAL, string(eof)
 	}
 }
 
+// scanTripleString scans multi-line string after opening quotes.
+// Common indentation and empty first and last lines are removed from the string.
+func (s *Scanner) scanTripleString() (token.Token, string) {
+	var buf bytes.Buffer
+	quotes := 0
+	for quotes < 3 {
+		switch s.ch {
+		case eof:
+			return token.ILLEGAL, buf.String()
+		case '\'':
+			quotes++
+		case '\\':
+			quotes = 0
+			buf.WriteRune(s.ch)
+			s.next()
+			if s.ch == eof {
+				continue
+			}
+		case '\r':
+			// normalize CRLF line endings
+			quotes = 0
+			s.next()
+			if s.ch != '\n' {
+				buf.WriteRune('\r')
+			}
+			continue
+		default:
+			quotes = 0
+		}
+		buf.WriteRune(s.ch)
+		s.next()
+	}
+	return token.TSTRING, unescape(dedent(buf.String()[:buf.Len()-quotes]))
+}
+
+// scanColor scans hex color: #fff or #3498DB, current char must be the first char after '#'.
+func (s *Scanner) scanColor() (token.Token, string) {
+	var buf bytes.Buffer
+	buf.WriteRune('#')
+	for isHexDigit(s.ch) {
+		buf.WriteRune(s.ch)
+		s.next()
+	}
+	if buf.Len() == 1 || isLetter(s.ch) || s.ch == '_' {
+		return token.ILLEGAL, buf.String()
+	}
+	return token.COLOR, buf.String()
+}
+
 func (s *Scanner) scanExpression() (token.Token, string) {
-	lit, ok := s.scanTo('`')
+	lit, ok := s.scanTo('`', false)
 	if ok {
 		return token.EXPR, lit
 	}
 	return token.ILLEGAL, lit
 }
 
-func (s *Scanner) scanTo(stop rune) (string, bool) {
+// scanTo scans string until stop char, escape sequences are unescaped when escapes is true.
+func (s *Scanner) scanTo(stop rune, escapes bool) (string, bool) {
 	var buf bytes.Buffer
 	for {
 		switch s.ch {
 		case stop:
 			s.next()
+			if escapes {
+				return unescape(buf.String()), true
+			}
 			return buf.String(), true
 		case '\n', eof:
 			return buf.String(), false
+		case '\\':
+			buf.WriteRune(s.ch)
+			s.next()
+			if escapes && s.ch != eof {
+				// escaped char can not stop string, escaped CRLF is one line break
+				crlf := s.ch == '\r'
+				buf.WriteRune(s.ch)
+				s.next()
+				if crlf && s.ch == '\n' {
+					buf.WriteRune(s.ch)
+					s.next()
+				}
+			}
 		default:
 			buf.WriteRune(s.ch)
 			s.next()
@@ -176,12 +314,79 @@ func (s *Scanner) scanTo(stop rune) (string, bool) {
 	}
 }
 
+// unescape replaces escape sequences: \' \" \` \\ \n \t and removes escaped line breaks.
+// Unknown escape sequences are kept as is.
+func unescape(str string) string {
+	if !strings.Contains(str, `\`) {
+		return str
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(str); i++ {
+		if str[i] != '\\' || i+1 == len(str) {
+			b.WriteByte(str[i])
+			continue
+		}
+		i++
+		switch str[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '\n':
+			// line continuation
+		case '\r':
+			// line continuation with CRLF
+			if i+1 < len(str) && str[i+1] == '\n' {
+				i++
+			}
+		case '\'', '"', '`', '\\':
+			b.WriteByte(str[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(str[i])
+		}
+	}
+	return b.String()
+}
+
+// dedent removes empty first and last lines and common indentation of lines.
+func dedent(str string) string {
+	lines := strings.Split(str, "\n")
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) < indent {
+			lines[i] = ""
+		} else if indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (s *Scanner) scanIdent() (tok token.Token, lit string) {
 	var buf bytes.Buffer
 	for {
 		buf.WriteRune(s.ch)
 		s.next()
-		if !isLetter(s.ch) && !isDigit(s.ch) && s.ch != '_' && s.ch != '.' {
+		if !isIdentPart(s.ch) {
 			break
 		}
 	}
@@ -189,20 +394,56 @@ func (s *Scanner) scanIdent() (tok token.Token, lit string) {
 }
 
 func (s *Scanner) next() {
-	ch, _, err := s.r.ReadRune()
+	s.offset += s.w
+	if s.ch == '\n' {
+		s.l++
+		s.c = 0
+	}
+
+	ch, w, err := s.r.ReadRune()
 	if err != nil {
+		if !s.exhausted {
+			s.c++
+			s.exhausted = true
+		}
 		s.ch = eof
+		s.w = 0
 		return
 	}
-	if ch == '\n' {
-		s.l++
-		s.c = 0
-	}
 	s.c++
 	s.ch = ch
+	s.w = w
+}
+
+// Raw returns source text of the last read token, e.g. string with quotes and escape sequences.
+func (s *Scanner) Raw() string {
+	return s.Source(s.pos, s.end)
+}
+
+// Source returns source text between start and end positions of already read tokens.
+func (s *Scanner) Source(start, end token.Position) string {
+	return string(s.src.Bytes()[start.Offset:end.Offset])
 }
 
-// LineInfo return line info.
+// LineInfo return line info of current position.
 func (s *Scanner) LineInfo() (uint, uint) {
 	return s.l, s.c
 }
+
+// Pos returns start position of the last read token.
+func (s *Scanner) Pos() token.Position {
+	return s.pos
+}
+
+// End returns position right after the last read token.
+func (s *Scanner) End() token.Position {
+	return s.end
+}
+
+func (s *Scanner) position() token.Position {
+	return token.Position{
+		Offset: s.offset,
+		Line:   int(s.l),
+		Column: int(s.c),
+	}
+}
@@ -35,3 +35,213 @@ func TestScanForNumber(t *testing.T) {
 		t.Fatalf("token %s, should be %s, lit %s", tok, token.ILLEGAL, lit)
 	}
 }
+
+func TestScanForRelationship(t *testing.T) {
+	s := sc("< > - <>")
+	for _, expected := range []token.Token{token.LSS, token.GTR, token.SUB, token.LSSGTR, token.EOF} {
+		if tok, lit := s.Read(); tok != expected {
+			t.Fatalf("token %s, should be %s, lit %s", tok, expected, lit)
+		}
+	}
+}
+
+func TestScanForQualifiedName(t *testing.T) {
+	s := sc(`billing."invoices".id`)
+	expected := []struct {
+		tok token.Token
+		lit string
+	}{
+		{token.IDENT, "billing"},
+		{token.PERIOD, "."},
+		{token.DSTRING, "invoices"},
+		{token.PERIOD, "."},
+		{token.IDENT, "id"},
+		{token.EOF, ""},
+	}
+	for _, e := range expected {
+		if tok, lit := s.Read(); tok != e.tok || lit != e.lit {
+			t.Fatalf("token %s, lit %s, should be %s, lit %s", tok, lit, e.tok, e.lit)
+		}
+	}
+}
+
+func TestScanForScientificNumber(t *testing.T) {
+	for _, str := range []string{"1e10", "1.5E-3", "2e+7"} {
+		if tok, lit := sc(str).Read(); tok != token.FLOAT || lit != str {
+			t.Fatalf("token %s, should be %s, lit %s", tok, token.FLOAT, lit)
+		}
+	}
+
+	if tok, lit := sc("1e").Read(); tok != token.ILLEGAL {
+		t.Fatalf("token %s, should be %s, lit %s", tok, token.ILLEGAL, lit)
+	}
+}
+
+func TestScanForPosition(t *testing.T) {
+	s := sc("Table users {\n  id int\n}")
+	expected := []token.Position{
+		{Offset: 0, Line: 1, Column: 1},
+		{Offset: 6, Line: 1, Column: 7},
+		{Offset: 12, Line: 1, Column: 13},
+		{Offset: 16, Line: 2, Column: 3},
+		{Offset: 19, Line: 2, Column: 6},
+		{Offset: 23, Line: 3, Column: 1},
+		{Offset: 24, Line: 3, Column: 2},
+	}
+	for _, e := range expected {
+		tok, lit := s.Read()
+		if pos := s.Pos(); pos != e {
+			t.Fatalf("token %s, lit %s, position %v, should be %v", tok, lit, pos, e)
+		}
+	}
+}
+
+func TestScanForBlockComment(t *testing.T) {
+	s := sc("/* users\n * table */ Table /**/ users /*/ not closed")
+	expected := []struct {
+		tok  token.Token
+		lit  string
+		line int
+	}{
+		{token.COMMENT, "/* users\n * table */", 1},
+		{token.TABLE, "Table", 2},
+		{token.COMMENT, "/**/", 2},
+		{token.IDENT, "users", 2},
+		{token.ILLEGAL, "/*/ not closed", 2},
+		{token.EOF, "", 2},
+	}
+	for _, e := range expected {
+		tok, lit := s.Read()
+		if tok != e.tok || lit != e.lit || s.Pos().Line != e.line {
+			t.Fatalf("token %s, lit %q, line %d, should be %s, lit %q, line %d", tok, lit, s.Pos().Line, e.tok, e.lit, e.line)
+		}
+	}
+}
+
+func TestScanForCRLF(t *testing.T) {
+	s := sc("Table users {\r\n\tid int // pk\r\n  note '''a\r\nb'''\r\n}")
+	expected := []struct {
+		tok token.Token
+		lit string
+		pos token.Position
+	}{
+		{token.TABLE, "Table", token.Position{Offset: 0, Line: 1, Column: 1}},
+		{token.IDENT, "users", token.Position{Offset: 6, Line: 1, Column: 7}},
+		{token.LBRACE, "{", token.Position{Offset: 12, Line: 1, Column: 13}},
+		{token.IDENT, "id", token.Position{Offset: 16, Line: 2, Column: 2}},
+		{token.IDENT, "int", token.Position{Offset: 19, Line: 2, Column: 5}},
+		{token.COMMENT, "// pk", token.Position{Offset: 23, Line: 2, Column: 9}},
+		{token.NOTE, "note", token.Position{Offset: 32, Line: 3, Column: 3}},
+		{token.TSTRING, "a\nb", token.Position{Offset: 37, Line: 3, Column: 8}},
+		{token.RBRACE, "}", token.Position{Offset: 49, Line: 5, Column: 1}},
+		{token.EOF, "", token.Position{Offset: 50, Line: 5, Column: 2}},
+	}
+	for _, e := range expected {
+		tok, lit := s.Read()
+		if tok != e.tok || lit != e.lit || s.Pos() != e.pos {
+			t.Fatalf("token %s, lit %q, position %v, should be %s, lit %q, position %v", tok, lit, s.Pos(), e.tok, e.lit, e.pos)
+		}
+	}
+}
+
+func TestScanForBOM(t *testing.T) {
+	s := sc("\uFEFFTable users")
+	if tok, lit := s.Read(); tok != token.TABLE {
+		t.Fatalf("token %s, should be %s, lit %s", tok, token.TABLE, lit)
+	}
+	if pos := s.Pos(); pos != (token.Position{Offset: 3, Line: 1, Column: 1}) {
+		t.Fatalf("position %v, should be offset 3 at 1:1", pos)
+	}
+}
+
+func TestScanForUnicodeIdent(t *testing.T) {
+	s := sc(`пользователи.имя "таблица ñ" 表_1 नाम तालिका१`)
+	expected := []struct {
+		tok token.Token
+		lit string
+	}{
+		{token.IDENT, "пользователи"},
+		{token.PERIOD, "."},
+		{token.IDENT, "имя"},
+		{token.DSTRING, "таблица ñ"},
+		{token.IDENT, "表_1"},
+		{token.IDENT, "नाम"},
+		{token.IDENT, "तालिका१"},
+		{token.EOF, ""},
+	}
+	for _, e := range expected {
+		if tok, lit := s.Read(); tok != e.tok || lit != e.lit {
+			t.Fatalf("token %s, lit %s, should be %s, lit %s", tok, lit, e.tok, e.lit)
+		}
+	}
+}
+
+func TestScanForString(t *testing.T) {
+	cases := []struct {
+		src string
+		tok token.Token
+		lit string
+		raw string
+	}{
+		{`'it\'s'`, token.STRING, "it's", `'it\'s'`},
+		{`"say \"hi\""`, token.DSTRING, `say "hi"`, `"say \"hi\""`},
+		{`'a\\b\nc\td\x'`, token.STRING, "a\\b\nc\td\\x", `'a\\b\nc\td\x'`},
+		{"'line \\\ncontinuation'", token.STRING, "line continuation", "'line \\\ncontinuation'"},
+		{"'a\\\r\nb'", token.STRING, "ab", "'a\\\r\nb'"},
+		{"\"a\\\r\nb\"", token.DSTRING, "ab", "\"a\\\r\nb\""},
+		{"''", token.STRING, "", "''"},
+		{`'not closed\'`, token.ILLEGAL, "not closed\\'", `'not closed\'`},
+		{"`a\\'b`", token.EXPR, "a\\'b", "`a\\'b`"},
+		{
+			"'''\n    # Title\n\n      * it\\'s\n    * 'item' \\\n    continued\n  '''",
+			token.TSTRING,
+			"# Title\n\n  * it's\n* 'item' continued",
+			"'''\n    # Title\n\n      * it\\'s\n    * 'item' \\\n    continued\n  '''",
+		},
+		{"'''a\\'''b'''", token.TSTRING, "a'''b", "'''a\\'''b'''"},
+		{"'''a\r\n  b'''", token.TSTRING, "a\n  b", "'''a\r\n  b'''"},
+	}
+	for _, c := range cases {
+		s := sc(c.src)
+		tok, lit := s.Read()
+		if tok != c.tok || lit != c.lit || s.Raw() != c.raw {
+			t.Fatalf("%q: token %s, lit %q, raw %q, should be %s, lit %q, raw %q", c.src, tok, lit, s.Raw(), c.tok, c.lit, c.raw)
+		}
+	}
+}
+
+func TestScanner_Source(t *testing.T) {
+	s := sc("\uFEFFnote: 'it\\'s' [x]")
+	s.Read()
+	s.Read()
+	tok, _ := s.Read()
+	start, end := s.Pos(), s.End()
+	s.Read()
+
+	if tok != token.STRING || s.Source(start, end) != `'it\'s'` {
+		t.Fatalf("token %s, source %q, should be STRING, source %q", tok, s.Source(start, end), `'it\'s'`)
+	}
+	if s.Raw() != "[" {
+		t.Fatalf("raw %q, should be %q", s.Raw(), "[")
+	}
+}
+
+func TestScanForColor(t *testing.T) {
+	cases := []struct {
+		src string
+		tok token.Token
+		lit string
+	}{
+		{"#fff", token.COLOR, "#fff"},
+		{"#3498DB", token.COLOR, "#3498DB"},
+		{"#1a2b3c]", token.COLOR, "#1a2b3c"},
+		{"#", token.ILLEGAL, "#"},
+		{"#xyz", token.ILLEGAL, "#"},
+		{"#12g", token.ILLEGAL, "#12"},
+	}
+	for _, c := range cases {
+		if tok, lit := sc(c.src).Read(); tok != c.tok || lit != c.lit {
+			t.Fatalf("%q: token %s, lit %s, should be %s, lit %s", c.src, tok, lit, c.tok, c.lit)
+		}
+	}
+}
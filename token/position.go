@@ -0,0 +1,15 @@
+package token
+
+import "fmt"
+
+// Position in source: byte offset starting at 0, line and column starting at 1.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// String returns position as line:column.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
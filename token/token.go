@@ -23,16 +23,20 @@ const (
 	DSTRING // "abc"
 	TSTRING // '''abc'''
 
-	EXPR // `now()`
+	EXPR  // `now()`
+	COLOR // #3498DB
 
 	_literalEnd
 
 	_operatorBeg
 
+	ADD // +
 	SUB // -
 	LSS // <
 	GTR // >
 
+	LSSGTR // <>
+
 	LPAREN // (
 	LBRACK // [
 	LBRACE // {
@@ -44,6 +48,7 @@ const (
 	RBRACE    // }
 	SEMICOLON // ;
 	COLON     // :
+	TILDE     // ~
 
 	_operatorEnd
 
@@ -55,6 +60,7 @@ const (
 	REF
 	AS
 	TABLEGROUP
+	TABLEPARTIAL
 
 	_keywordEnd
 
@@ -99,11 +105,15 @@ var Tokens = [...]string{
 	DSTRING: "DSTRING",
 	TSTRING: "TSTRING",
 	EXPR:    "EXPR",
+	COLOR:   "COLOR",
 
+	ADD: "+",
 	SUB: "-",
 	LSS: "<",
 	GTR: ">",
 
+	LSSGTR: "<>",
+
 	LPAREN: "(",
 	LBRACK: "[",
 	LBRACE: "{",
@@ -114,15 +124,17 @@ var Tokens = [...]string{
 
 	SEMICOLON: ";",
 	COLON:     ":",
+	TILDE:     "~",
 	COMMA:     ",",
 	PERIOD:    ".",
 
-	PROJECT:    "PROJECT",
-	TABLE:      "TABLE",
-	ENUM:       "ENUM",
-	REF:        "REF",
-	AS:         "AS",
-	TABLEGROUP: "TABLEGROUP",
+	PROJECT:      "PROJECT",
+	TABLE:        "TABLE",
+	ENUM:         "ENUM",
+	REF:          "REF",
+	AS:           "AS",
+	TABLEGROUP:   "TABLEGROUP",
+	TABLEPARTIAL: "TABLEPARTIAL",
 
 	PRIMARY:     "PRIMARY",
 	KEY:         "KEY",
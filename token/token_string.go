@@ -20,55 +20,60 @@ func _() {
 	_ = x[DSTRING-9]
 	_ = x[TSTRING-10]
 	_ = x[EXPR-11]
-	_ = x[_literalEnd-12]
-	_ = x[_operatorBeg-13]
-	_ = x[SUB-14]
-	_ = x[LSS-15]
-	_ = x[GTR-16]
-	_ = x[LPAREN-17]
-	_ = x[LBRACK-18]
-	_ = x[LBRACE-19]
-	_ = x[COMMA-20]
-	_ = x[PERIOD-21]
-	_ = x[RPAREN-22]
-	_ = x[RBRACK-23]
-	_ = x[RBRACE-24]
-	_ = x[SEMICOLON-25]
-	_ = x[COLON-26]
-	_ = x[_operatorEnd-27]
-	_ = x[_keywordBeg-28]
-	_ = x[PROJECT-29]
-	_ = x[TABLE-30]
-	_ = x[ENUM-31]
-	_ = x[REF-32]
-	_ = x[AS-33]
-	_ = x[TABLEGROUP-34]
-	_ = x[_keywordEnd-35]
-	_ = x[_miscBeg-36]
-	_ = x[PRIMARY-37]
-	_ = x[KEY-38]
-	_ = x[PK-39]
-	_ = x[NOTE-40]
-	_ = x[UNIQUE-41]
-	_ = x[NOT-42]
-	_ = x[NULL-43]
-	_ = x[INCREMENT-44]
-	_ = x[DEFAULT-45]
-	_ = x[HEADERCOLOR-46]
-	_ = x[INDEXES-47]
-	_ = x[TYPE-48]
-	_ = x[DELETE-49]
-	_ = x[UPDATE-50]
-	_ = x[NO-51]
-	_ = x[ACTION-52]
-	_ = x[RESTRICT-53]
-	_ = x[SET-54]
-	_ = x[_miscEnd-55]
+	_ = x[COLOR-12]
+	_ = x[_literalEnd-13]
+	_ = x[_operatorBeg-14]
+	_ = x[ADD-15]
+	_ = x[SUB-16]
+	_ = x[LSS-17]
+	_ = x[GTR-18]
+	_ = x[LSSGTR-19]
+	_ = x[LPAREN-20]
+	_ = x[LBRACK-21]
+	_ = x[LBRACE-22]
+	_ = x[COMMA-23]
+	_ = x[PERIOD-24]
+	_ = x[RPAREN-25]
+	_ = x[RBRACK-26]
+	_ = x[RBRACE-27]
+	_ = x[SEMICOLON-28]
+	_ = x[COLON-29]
+	_ = x[TILDE-30]
+	_ = x[_operatorEnd-31]
+	_ = x[_keywordBeg-32]
+	_ = x[PROJECT-33]
+	_ = x[TABLE-34]
+	_ = x[ENUM-35]
+	_ = x[REF-36]
+	_ = x[AS-37]
+	_ = x[TABLEGROUP-38]
+	_ = x[TABLEPARTIAL-39]
+	_ = x[_keywordEnd-40]
+	_ = x[_miscBeg-41]
+	_ = x[PRIMARY-42]
+	_ = x[KEY-43]
+	_ = x[PK-44]
+	_ = x[NOTE-45]
+	_ = x[UNIQUE-46]
+	_ = x[NOT-47]
+	_ = x[NULL-48]
+	_ = x[INCREMENT-49]
+	_ = x[DEFAULT-50]
+	_ = x[HEADERCOLOR-51]
+	_ = x[INDEXES-52]
+	_ = x[TYPE-53]
+	_ = x[DELETE-54]
+	_ = x[UPDATE-55]
+	_ = x[NO-56]
+	_ = x[ACTION-57]
+	_ = x[RESTRICT-58]
+	_ = x[SET-59]
+	_ = x[_miscEnd-60]
 }
 
-const _Token_name = "ILLEGALEOFCOMMENT_literalBegIDENTINTFLOATIMAGSTRINGDSTRINGTSTRINGEXPR_literalEnd_operatorBegSUBLSSGTRLPARENLBRACKLBRACECOMMAPERIODRPARENRBRACKRBRACESEMICOLONCOLON_operatorEnd_keywordBegPROJECTTABLEENUMREFASTABLEGROUP_keywordEnd_miscBegPRIMARYKEYPKNOTEUNIQUENOTNULLINCREMENTDEFAULTHEADERCOLORINDEXESTYPEDELETEUPDATENOACTIONRESTRICTSET_miscEnd"
+const _Token_name = "ILLEGALEOFCOMMENT_literalBegIDENTINTFLOATIMAGSTRINGDSTRINGTSTRINGEXPRCOLOR_literalEnd_operatorBegADDSUBLSSGTRLSSGTRLPARENLBRACKLBRACECOMMAPERIODRPARENRBRACKRBRACESEMICOLONCOLONTILDE_operatorEnd_keywordBegPROJECTTABLEENUMREFASTABLEGROUPTABLEPARTIAL_keywordEnd_miscBegPRIMARYKEYPKNOTEUNIQUENOTNULLINCREMENTDEFAULTHEADERCOLORINDEXESTYPEDELETEUPDATENOACTIONRESTRICTSET_miscEnd"
 
-var _Token_index = [...]uint16{0, 7, 10, 17, 28, 33, 36, 41, 45, 51, 58, 65, 69, 80, 92, 95, 98, 101, 107, 113, 119, 124, 130, 136, 142, 148, 157, 162, 174, 185, 192, 197, 201, 204, 206, 216, 227, 235, 242, 245, 247, 251, 257, 260, 264, 273, 280, 291, 298, 302, 308, 314, 316, 322, 330, 333, 341}
+var _Token_index = [...]uint16{0, 7, 10, 17, 28, 33, 36, 41, 45, 51, 58, 65, 69, 74, 85, 97, 100, 103, 106, 109, 115, 121, 127, 133, 138, 144, 150, 156, 162, 171, 176, 181, 193, 204, 211, 216, 220, 223, 225, 235, 247, 258, 266, 273, 276, 278, 282, 288, 291, 295, 304, 311, 322, 329, 333, 339, 345, 347, 353, 361, 364, 372}
 
 func (i Token) String() string {
 	if i < 0 || i >= Token(len(_Token_index)-1) {
@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"strings"
+
+	"github.com/artarts36/dbml-go/core"
+)
+
+// definitions maps key of definition to span of its first declaration.
+type definitions map[string]core.Span
+
+// define registers definition and reports it when key is already defined.
+func (v *validator) define(defined definitions, key string, span core.Span, format string, args ...any) {
+	first, ok := defined[key]
+	if !ok {
+		defined[key] = span
+		return
+	}
+
+	err := v.errorf(span, format+" is already defined at %s", append(args, first.Start)...)
+	err.Related = append(err.Related, first)
+}
+
+// validateDuplicates reports duplicated tables, partials, columns, enums, enum values and table groups.
+// Enum values are compared case-sensitively, other names case-insensitively.
+func (v *validator) validateDuplicates(dbml *core.DBML) {
+	tables := definitions{}
+	for _, table := range dbml.Tables {
+		v.define(tables, core.LookupKey(table.Schema, table.Name), table.Span, "table %s", core.QualifiedName(table.Schema, table.Name))
+		// alias equal to name of table without schema is the same definition
+		if table.As != "" && !(table.Schema == "" && strings.EqualFold(table.As, table.Name)) {
+			v.define(tables, core.LookupKey("", table.As), table.Span, "table %s", table.As)
+		}
+		v.validateColumnDuplicates("table "+table.Name, table.Columns)
+	}
+
+	partials := definitions{}
+	for _, partial := range dbml.TablePartials {
+		v.define(partials, strings.ToLower(partial.Name), partial.Span, "table partial %s", partial.Name)
+		v.validateColumnDuplicates("table partial "+partial.Name, partial.Columns)
+	}
+
+	enums := definitions{}
+	for _, enum := range dbml.Enums {
+		v.define(enums, core.LookupKey(enum.Schema, enum.Name), enum.Span, "enum %s", core.QualifiedName(enum.Schema, enum.Name))
+
+		values := definitions{}
+		for _, value := range enum.Values {
+			v.define(values, value.Name, value.Span, "enum %s: value %s", enum.Name, value.Name)
+		}
+	}
+
+	groups := definitions{}
+	for _, group := range dbml.TableGroups {
+		v.define(groups, strings.ToLower(group.Name), group.Span, "table group %s", group.Name)
+	}
+}
+
+func (v *validator) validateColumnDuplicates(owner string, columns []core.Column) {
+	defined := definitions{}
+	for _, column := range columns {
+		v.define(defined, strings.ToLower(column.Name), column.Span, "%s: column %s", owner, column.Name)
+	}
+}
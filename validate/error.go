@@ -0,0 +1,24 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/internal/errlist"
+)
+
+// Error describes invalid definition.
+type Error struct {
+	// Span of invalid definition.
+	Span    core.Span
+	Message string
+	// Related spans, e.g. previous definition of duplicated definition.
+	Related []core.Span
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("[%d:%d] %s", e.Span.Start.Line, e.Span.Start.Column, e.Message)
+}
+
+// ErrorList is list of validation errors ordered by position.
+type ErrorList = errlist.List[*Error]
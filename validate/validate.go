@@ -0,0 +1,216 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/artarts36/dbml-go/core"
+)
+
+type validator struct {
+	lookup *core.Lookup
+	// groups of tables with span of group member
+	groups map[*core.Table]groupMember
+
+	errs ErrorList
+}
+
+type groupMember struct {
+	group string
+	span  core.Span
+}
+
+// Validate checks that references between definitions are valid:
+//   - refs and inline refs point to existing tables and columns;
+//   - table group members are tables, table belongs to at most one group;
+//   - schema qualified column types are declared enums, unqualified types are not checked
+//     as they can't be told apart from database specific types;
+//   - index fields are columns of table;
+//   - definitions are not duplicated.
+//
+// Names are compared case-insensitively, tables and enums without schema belong to "public" schema.
+// Returns nil for valid DBML, otherwise ErrorList.
+func Validate(dbml *core.DBML) error {
+	v := &validator{
+		groups: map[*core.Table]groupMember{},
+	}
+
+	v.validateDuplicates(dbml)
+
+	resolved := v.resolvePartials(dbml)
+
+	// duplicates are already reported, lookup finds the first definition
+	v.lookup = core.NewLookup(resolved)
+
+	for i := range resolved.Tables {
+		v.validateTable(&resolved.Tables[i])
+	}
+	for _, ref := range resolved.Refs {
+		for _, rel := range ref.Relationships {
+			v.validateRelationship(rel)
+		}
+	}
+	for _, group := range resolved.TableGroups {
+		v.validateTableGroup(group)
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		return v.errs[i].Span.Start.Offset < v.errs[j].Span.Start.Offset
+	})
+
+	return v.errs
+}
+
+// resolvePartials reports injections of unknown partials and returns DBML with resolved known partials.
+func (v *validator) resolvePartials(dbml *core.DBML) *core.DBML {
+	partials := map[string]bool{}
+	for _, partial := range dbml.TablePartials {
+		partials[strings.ToLower(partial.Name)] = true
+	}
+
+	known := *dbml
+	known.Tables = make([]core.Table, 0, len(dbml.Tables))
+	for _, table := range dbml.Tables {
+		injections := make([]core.TablePartialInjection, 0, len(table.Partials))
+		for _, injection := range table.Partials {
+			if !partials[strings.ToLower(injection.Name)] {
+				v.errorf(table.Span, "table %s: table partial %s not found", table.Name, injection.Name)
+				continue
+			}
+			injections = append(injections, injection)
+		}
+		table.Partials = injections
+		known.Tables = append(known.Tables, table)
+	}
+
+	resolved, err := known.ResolveTablePartials()
+	if err != nil {
+		// all injections are known
+		return &known
+	}
+	return resolved
+}
+
+func (v *validator) validateTable(table *core.Table) {
+	for _, column := range table.Columns {
+		if column.Settings.Ref.Type != core.None {
+			v.validateEndpoint(
+				column.Span,
+				fmt.Sprintf("column %s.%s: ref", table.Name, column.Name),
+				column.Settings.Ref.To,
+			)
+
+			if len(column.Settings.Ref.To.Columns) != 1 {
+				from := core.RelationshipEndpoint{Schema: table.Schema, Table: table.Name, Columns: []string{column.Name}}
+				v.errorf(
+					column.Span,
+					"column %s.%s: ref: %s and %s have different number of columns",
+					table.Name,
+					column.Name,
+					from,
+					column.Settings.Ref.To,
+				)
+			}
+		}
+
+		if column.DataType.Schema != "" {
+			if v.lookup.Enum(column.DataType.Schema, column.DataType.Name) == nil {
+				v.errorf(
+					column.Span,
+					"column %s.%s: enum %s.%s not found",
+					table.Name,
+					column.Name,
+					column.DataType.Schema,
+					column.DataType.Name,
+				)
+			}
+		}
+	}
+
+	indexes := definitions{}
+	for _, index := range table.Indexes {
+		if index.Settings.Name != "" {
+			v.define(
+				indexes,
+				strings.ToLower(index.Settings.Name),
+				index.Span,
+				"table %s: index %s",
+				table.Name,
+				index.Settings.Name,
+			)
+		}
+
+		for _, field := range index.Fields {
+			if field.Type == core.IndexFieldTypeColumn && table.Column(field.Value) == nil {
+				v.errorf(index.Span, "index of table %s: column %s not found", table.Name, field.Value)
+			}
+		}
+	}
+}
+
+func (v *validator) validateRelationship(rel core.Relationship) {
+	v.validateEndpoint(rel.Span, "ref", rel.From)
+	v.validateEndpoint(rel.Span, "ref", rel.To)
+
+	if len(rel.From.Columns) != len(rel.To.Columns) {
+		v.errorf(
+			rel.Span,
+			"ref: %s and %s have different number of columns",
+			rel.From,
+			rel.To,
+		)
+	}
+}
+
+func (v *validator) validateEndpoint(span core.Span, subject string, endpoint core.RelationshipEndpoint) {
+	table := v.lookup.Table(endpoint.Schema, endpoint.Table)
+	if table == nil {
+		v.errorf(span, "%s: table %s not found", subject, core.QualifiedName(endpoint.Schema, endpoint.Table))
+		return
+	}
+
+	for _, column := range endpoint.Columns {
+		if table.Column(column) == nil {
+			v.errorf(span, "%s: column %s.%s not found", subject, core.QualifiedName(endpoint.Schema, endpoint.Table), column)
+		}
+	}
+}
+
+func (v *validator) validateTableGroup(group core.TableGroup) {
+	for _, member := range group.Members {
+		table := v.lookup.Table(member.Schema, member.Name)
+		if table == nil {
+			v.errorf(member.Span, "table group %s: table %s not found", group.Name, core.QualifiedName(member.Schema, member.Name))
+			continue
+		}
+
+		first, ok := v.groups[table]
+		if !ok {
+			v.groups[table] = groupMember{group: group.Name, span: member.Span}
+			continue
+		}
+		err := v.errorf(
+			member.Span,
+			"table group %s: table %s is already in table group %s at %s",
+			group.Name,
+			core.QualifiedName(member.Schema, member.Name),
+			first.group,
+			first.span.Start,
+		)
+		err.Related = append(err.Related, first.span)
+	}
+}
+
+func (v *validator) errorf(span core.Span, format string, args ...any) *Error {
+	err := &Error{
+		Span:    span,
+		Message: fmt.Sprintf(format, args...),
+	}
+	v.errs = append(v.errs, err)
+	return err
+}
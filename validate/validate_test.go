@@ -0,0 +1,410 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artarts36/dbml-go/core"
+	"github.com/artarts36/dbml-go/parser"
+)
+
+func parse(t *testing.T, spec string) *core.DBML {
+	t.Helper()
+
+	dbml, err := parser.Parse(context.Background(), strings.NewReader(spec))
+	require.NoError(t, err)
+
+	return dbml
+}
+
+// validateErrors returns errors of Validate, nil for valid DBML.
+func validateErrors(t *testing.T, dbml *core.DBML) ErrorList {
+	t.Helper()
+
+	err := Validate(dbml)
+	if err == nil {
+		return nil
+	}
+	var errs ErrorList
+	require.ErrorAs(t, err, &errs)
+	return errs
+}
+
+func messages(errs ErrorList) []string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Message)
+	}
+	return msgs
+}
+
+func TestValidate_Valid(t *testing.T) {
+	dbml := parse(t, `
+Enum billing.status {
+  paid
+}
+
+TablePartial audit {
+  created_at timestamp
+}
+
+Table Users as U {
+  id int [pk]
+  email varchar
+  ~audit
+
+  indexes {
+    (email, created_at)
+    `+"`lower(email)`"+`
+  }
+}
+
+Table billing.invoices {
+  id int
+  user_id int [ref: > U.id]
+  country varchar
+  status billing.status
+}
+
+Table merchants {
+  id int
+  country varchar
+}
+
+Ref: billing.invoices.(id, country) > merchants.(ID, Country)
+Ref: billing.invoices.user_id > public.users.id
+
+TableGroup g {
+  users
+  billing.invoices
+}
+`)
+
+	assert.NoError(t, Validate(dbml))
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected []string
+	}{
+		{
+			Title: "ref to unknown table",
+			Spec: `
+Table users {
+  id int
+}
+Ref: posts.user_id > users.id`,
+			Expected: []string{"ref: table posts not found"},
+		},
+		{
+			Title: "ref to unknown column",
+			Spec: `
+Table users {
+  id int
+}
+Table posts {
+  user_id int
+}
+Ref: posts.user_id > users.uuid`,
+			Expected: []string{"ref: column users.uuid not found"},
+		},
+		{
+			Title: "ref to table in other schema",
+			Spec: `
+Table users {
+  id int
+}
+Table posts {
+  user_id int
+}
+Ref: posts.user_id > auth.users.id`,
+			Expected: []string{"ref: table auth.users not found"},
+		},
+		{
+			Title: "composite ref with different number of columns",
+			Spec: `
+Table users {
+  id int
+  country varchar
+}
+Table posts {
+  user_id int
+}
+Ref: posts.(user_id) > users.(id, country)`,
+			Expected: []string{"ref: posts.user_id and users.(id, country) have different number of columns"},
+		},
+		{
+			Title: "inline ref to unknown column",
+			Spec: `
+Table users {
+  id int
+}
+Table posts {
+  user_id int [ref: > users.uuid]
+}`,
+			Expected: []string{"column posts.user_id: ref: column users.uuid not found"},
+		},
+		{
+			Title: "inline ref to composite key",
+			Spec: `
+Table users {
+  id int
+  country varchar
+}
+Table posts {
+  user_id int [ref: > users.(id, country)]
+}`,
+			Expected: []string{
+				"column posts.user_id: ref: posts.user_id and users.(id, country) have different number of columns",
+			},
+		},
+		{
+			Title: "table group member is not table",
+			Spec: `
+Table users {
+  id int
+}
+TableGroup g {
+  users
+  posts
+}`,
+			Expected: []string{"table group g: table posts not found"},
+		},
+		{
+			Title: "column typed as undeclared enum",
+			Spec: `
+Table users {
+  status billing.status
+}`,
+			Expected: []string{"column users.status: enum billing.status not found"},
+		},
+		{
+			Title: "column typed as unqualified undeclared enum is not checked",
+			Spec: `
+Table users {
+  status user_status
+  location geometry(point, 4326)
+}`,
+			Expected: []string{},
+		},
+		{
+			Title: "index field is not column",
+			Spec: `
+Table users {
+  id int
+
+  indexes {
+    (id, email)
+  }
+}`,
+			Expected: []string{"index of table users: column email not found"},
+		},
+		{
+			Title: "unknown table partial",
+			Spec: `
+Table users {
+  id int
+  ~audit
+}`,
+			Expected: []string{"table users: table partial audit not found"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Title, func(t *testing.T) {
+			assert.Equal(t, c.Expected, messages(validateErrors(t, parse(t, c.Spec))))
+		})
+	}
+}
+
+func TestValidate_Positions(t *testing.T) {
+	spec := `Table users {
+  id int
+  status billing.status
+}
+
+Ref: users.id > posts.id
+`
+	errs := validateErrors(t, parse(t, spec))
+	require.Len(t, errs, 2)
+
+	assert.Equal(t, "status billing.status", spec[errs[0].Span.Start.Offset:errs[0].Span.End.Offset])
+	assert.Equal(t, 3, errs[0].Span.Start.Line)
+	assert.Equal(t, "[3:3] column users.status: enum billing.status not found", errs[0].Error())
+
+	assert.Equal(t, "users.id > posts.id", spec[errs[1].Span.Start.Offset:errs[1].Span.End.Offset])
+	assert.Equal(t, 6, errs[1].Span.Start.Line)
+
+	var validationErr *Error
+	require.True(t, errors.As(errs, &validationErr))
+	assert.Equal(t, errs[0], validationErr)
+	assert.True(t, errors.Is(errs, errs[1]))
+	assert.Equal(t, "[3:3] column users.status: enum billing.status not found (and 1 more errors)", errs.Error())
+}
+
+func TestValidate_Duplicates(t *testing.T) {
+	cases := []struct {
+		Title    string
+		Spec     string
+		Expected []string
+	}{
+		{
+			Title: "tables",
+			Spec: `
+Table users {
+  id int
+}
+Table public.Users {
+  id int
+}
+Table billing.users {
+  id int
+}`,
+			Expected: []string{"table public.Users is already defined at 2:1"},
+		},
+		{
+			Title: "table alias",
+			Spec: `
+Table users {
+  id int
+}
+Table accounts as users {
+  id int
+}`,
+			Expected: []string{"table users is already defined at 2:1"},
+		},
+		{
+			Title: "table alias equal to table name",
+			Spec: `
+Table users as users {
+  id int
+}
+Table Accounts as accounts {
+  id int
+}`,
+			Expected: []string{},
+		},
+		{
+			Title: "columns",
+			Spec: `
+Table users {
+  id int
+  ID int
+}`,
+			Expected: []string{"table users: column ID is already defined at 3:3"},
+		},
+		{
+			Title: "enums and enum values",
+			Spec: `
+Enum status {
+  active
+  Active
+  active
+}
+Enum STATUS {
+  active
+}`,
+			Expected: []string{
+				"enum status: value active is already defined at 3:3",
+				"enum STATUS is already defined at 2:1",
+			},
+		},
+		{
+			Title: "index names",
+			Spec: `
+Table users {
+  id int
+  email varchar
+
+  indexes {
+    id [name: 'users_idx']
+    email [name: 'USERS_IDX']
+  }
+}`,
+			Expected: []string{"table users: index USERS_IDX is already defined at 7:5"},
+		},
+		{
+			Title: "table groups and partials",
+			Spec: `
+TablePartial audit {
+  created_at timestamp
+}
+TablePartial Audit {
+  created_at timestamp
+}
+Table users {
+  id int
+}
+TableGroup g {
+  users
+}
+TableGroup G {
+  users
+}`,
+			Expected: []string{
+				"table partial Audit is already defined at 2:1",
+				"table group G is already defined at 11:1",
+				"table group G: table users is already in table group g at 12:3",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Title, func(t *testing.T) {
+			assert.Equal(t, c.Expected, messages(validateErrors(t, parse(t, c.Spec))))
+		})
+	}
+}
+
+func TestValidate_TableGroups(t *testing.T) {
+	spec := `Table users {
+  id int
+}
+Table billing.invoices {
+  id int
+}
+TableGroup a {
+  users
+  billing.invoices
+}
+TableGroup b {
+  billing.invoices
+  invoices
+}
+`
+	errs := validateErrors(t, parse(t, spec))
+	require.Len(t, errs, 2)
+
+	assert.Equal(t, "table group b: table billing.invoices is already in table group a at 9:3", errs[0].Message)
+	assert.Equal(t, "billing.invoices", spec[errs[0].Span.Start.Offset:errs[0].Span.End.Offset])
+	require.Len(t, errs[0].Related, 1)
+	assert.Equal(t, 9, errs[0].Related[0].Start.Line)
+
+	assert.Equal(t, "table group b: table invoices not found", errs[1].Message)
+	assert.Equal(t, "invoices", spec[errs[1].Span.Start.Offset:errs[1].Span.End.Offset])
+}
+
+func TestValidate_Duplicates_Related(t *testing.T) {
+	spec := `Table users {
+  id int
+}
+
+Table users {
+  id int
+}
+`
+	errs := validateErrors(t, parse(t, spec))
+	require.Len(t, errs, 1)
+
+	assert.Equal(t, 5, errs[0].Span.Start.Line)
+	require.Len(t, errs[0].Related, 1)
+	assert.Equal(t, 1, errs[0].Related[0].Start.Line)
+	assert.Equal(t, "Table users {\n  id int\n}", spec[errs[0].Related[0].Start.Offset:errs[0].Related[0].End.Offset])
+}